@@ -0,0 +1,259 @@
+package endorsers
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"rsc-tools/internal/citizens"
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/policy"
+	"rsc-tools/internal/report"
+	"rsc-tools/internal/source"
+	"rsc-tools/violators"
+)
+
+// Ways of scoring endorsers.
+const (
+	// Equal scores each endorser by the percentage of violators endorsed.
+	Equal = "equal"
+	// Overage weighs each violator by how far over its cap it is, scoring
+	// each endorser by the percentage of the total overage endorsed.
+	Overage = "overage"
+	// Relative weighs each violator by how far over its cap it is as a share
+	// of that cap, so that 10 over a cap of 10 counts for more than 10 over a
+	// cap of 50. A cap of 0 is taken as 1, so every weight is a ratio.
+	Relative = "relative"
+	// Count scores each endorser by the number of violators endorsed.
+	Count = "count"
+)
+
+type Args struct {
+	User     ns.NationName
+	Citizens citizens.Source
+	Delegate ns.NationName
+	Region   string
+	Excluded []ns.NationName
+	Policy   policy.Policy
+	Source   source.Options
+	Output   report.Options
+	Verbose  bool
+
+	// Score is how endorsers are scored, one of Equal, Overage, Relative or
+	// Count. Empty means Equal.
+	Score string
+
+	// Violators, if not nil, are the violators to check, read from a
+	// violators report instead of worked out again.
+	Violators []ns.NationName
+
+	// Top, if positive, checks only the Top violators furthest over their
+	// cap, or the first Top listed in Violators. Otherwise every violator,
+	// or every nation in Violators, is checked.
+	Top int
+}
+
+// Endorser is a nation endorsing violators. Overage is the endorsements over
+// cap of the violators it endorses, summed.
+type Endorser struct {
+	Name      ns.NationName
+	Score     float64
+	Overage   int
+	Endorsing []ns.NationName
+}
+
+// weight returns how much v counts towards the score of each of its endorsers
+// under score, before any percentage is taken.
+func weight(score string, v violators.Violator) float64 {
+	switch score {
+	case Overage:
+		return float64(v.Over)
+	case Relative:
+		endocap := v.Cap
+		if endocap < 1 {
+			endocap = 1
+		}
+		return float64(v.Over) / float64(endocap)
+	default:
+		return 1
+	}
+}
+
+// Aggregate scores the nations endorsing found, highest score first and then
+// by name, reading each violator's endorsers with endorsersOf. score is one
+// of Equal, Overage, Relative or Count; empty means Equal. With no violators, or
+// none with endorsers, it returns an empty slice. Blank names are skipped.
+func Aggregate(found []violators.Violator, endorsersOf func(ns.NationName) ([]ns.NationName, error), score string) ([]Endorser, error) {
+	endorsers := make(map[ns.NationName]Endorser)
+
+	var total float64
+	for _, v := range found {
+		if v.Name != "" {
+			total += weight(score, v)
+		}
+	}
+
+	for _, v := range found {
+		violator := v.Name
+		if violator == "" {
+			continue
+		}
+
+		endorsements, err := endorsersOf(violator)
+		if err != nil {
+			return nil, fmt.Errorf("getting violator endorsements: %w", err)
+		}
+
+		for _, endorser := range endorsements {
+			if endorser == "" {
+				continue
+			}
+
+			entry := endorsers[endorser]
+			entry.Name = endorser
+			entry.Score += weight(score, v)
+			entry.Overage += v.Over
+			entry.Endorsing = append(entry.Endorsing, violator)
+			endorsers[endorser] = entry
+		}
+	}
+
+	sortedEndorsers := make([]Endorser, 0, len(endorsers))
+	for _, endorser := range endorsers {
+		if score != Count && total > 0 {
+			endorser.Score = 100 * endorser.Score / total
+		}
+		sortedEndorsers = append(sortedEndorsers, endorser)
+	}
+
+	sort.Slice(sortedEndorsers, func(i, j int) bool {
+		if sortedEndorsers[i].Score != sortedEndorsers[j].Score {
+			return sortedEndorsers[i].Score > sortedEndorsers[j].Score
+		}
+		return sortedEndorsers[i].Name < sortedEndorsers[j].Name
+	})
+
+	return sortedEndorsers, nil
+}
+
+func buildReport(args Args, found []violators.Violator, endorsers []Endorser) report.Report {
+	score := "percentage"
+	if args.Score == Count {
+		score = "violators"
+	}
+
+	table := report.Table{Name: "Endorsers", Columns: []string{"nation", score, "overage"}}
+	if len(found) == 0 {
+		table.Empty = "No endocap violators, so no endorsers to report."
+	} else {
+		table.Empty = "None of the endocap violators have any endorsements."
+	}
+	if args.Verbose {
+		table.Columns = append(table.Columns, "endorsing")
+	}
+
+	for _, endorser := range endorsers {
+		row := []interface{}{endorser.Name, report.Percent(endorser.Score), endorser.Overage}
+		if args.Score == Count {
+			row[1] = int(endorser.Score)
+		}
+		if args.Verbose {
+			row = append(row, endorser.Endorsing)
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return report.Report{Name: "endorsers", Title: "Endorsers of Endocap Violators", Tables: []report.Table{table}}
+}
+
+// findViolators returns the violators to check: those given in args, or else
+// the ones the violators tool would report, ranked the same way. Given
+// violators keep their order, but how far each is over its cap is worked out
+// again, since a report may list only names; those no longer over have an
+// overage of 0.
+func findViolators(src source.Source, g *graph.EndorsementGraph, args Args) ([]violators.Violator, error) {
+	vargs := violators.Args{
+		User:     args.User,
+		Citizens: args.Citizens,
+		Delegate: args.Delegate,
+		Region:   args.Region,
+		Excluded: args.Excluded,
+		Policy:   args.Policy,
+		Nations:  args.Violators,
+	}
+
+	if args.Violators == nil {
+		vargs.Top = args.Top
+		return violators.Find(src, g, vargs)
+	}
+
+	current, err := violators.Find(src, g, vargs)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[ns.NationName]violators.Violator, len(current))
+	for _, v := range current {
+		byName[v.Name] = v
+	}
+
+	found := make([]violators.Violator, 0, len(args.Violators))
+	for _, violator := range args.Violators {
+		v, ok := byName[violator]
+		if !ok {
+			v = violators.Violator{Name: violator}
+		}
+		found = append(found, v)
+	}
+
+	if args.Top > 0 && len(found) > args.Top {
+		found = found[:args.Top]
+	}
+
+	return found, nil
+}
+
+// Run reports the nations endorsing the region's endocap violators.
+func Run(args Args) error {
+	switch args.Score {
+	case "":
+		args.Score = Equal
+	case Equal, Overage, Relative, Count:
+	default:
+		return fmt.Errorf("unknown score %q: use %s, %s, %s or %s", args.Score, Equal, Overage, Relative, Count)
+	}
+
+	client := ns.NewClient("Endorsers", args.User.String())
+
+	src, err := source.Open(args.Source, client, args.Region)
+	if err != nil {
+		return err
+	}
+
+	g := graph.New(src, args.Region)
+
+	found, err := findViolators(src, g, args)
+	if err != nil {
+		return err
+	}
+
+	if len(found) == 0 {
+		fmt.Fprintln(os.Stderr, "No endocap violators found")
+	}
+
+	fmt.Fprintln(os.Stderr, "Getting violator endorsements")
+	endorsers, err := Aggregate(found, g.EndorsersOf, args.Score)
+	if err != nil {
+		return err
+	}
+
+	path, err := report.Save(buildReport(args, found, endorsers), args.Output, report.Text)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Results written to %s\n", path)
+
+	return nil
+}
@@ -0,0 +1,288 @@
+package endorsers
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"rsc-tools/internal/citizens"
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/nstest"
+	"rsc-tools/internal/policy"
+	"rsc-tools/violators"
+)
+
+// region has two violators under a flat cap of 2: a, with 6 endorsements, and
+// x, with 3. e2 and e3 endorse both.
+var region = nstest.Region{
+	Name:     "europeia",
+	Delegate: "le_libertia",
+	Nations: []nstest.Nation{
+		{Name: "le_libertia", WA: true, Endorsements: []string{"a", "b", "x"}},
+		{Name: "a", WA: true, Endorsements: []string{"e1", "e2", "e3", "e4", "e5", "e6"}},
+		{Name: "b", WA: true, Endorsements: []string{"e1", "e2"}},
+		{Name: "x", WA: true, Endorsements: []string{"e2", "e3", "e7"}},
+	},
+}
+
+func TestFindViolators(t *testing.T) {
+	// many is a published list longer than violators.DefaultTop, none of
+	// whom are over their cap any more.
+	var many []ns.NationName
+	var manyWant []violators.Violator
+	for _, name := range nstest.Names("given", violators.DefaultTop+5) {
+		many = append(many, ns.NationName(name))
+		manyWant = append(manyWant, violators.Violator{Name: ns.NationName(name)})
+	}
+
+	tests := []struct {
+		name      string
+		violators []ns.NationName
+		top       int
+		want      []violators.Violator
+	}{
+		{"worked out like violators", nil, 0, []violators.Violator{{Name: "a", Over: 4, Tier: "standard", Cap: 2, Endorsements: 6, EndorsesDelegate: true}, {Name: "x", Over: 1, Tier: "standard", Cap: 2, Endorsements: 3, EndorsesDelegate: true}}},
+		{"top of worked out", nil, 1, []violators.Violator{{Name: "a", Over: 4, Tier: "standard", Cap: 2, Endorsements: 6, EndorsesDelegate: true}}},
+		{"given list", []ns.NationName{"x", "b"}, 0, []violators.Violator{{Name: "x", Over: 1, Tier: "standard", Cap: 2, Endorsements: 3, EndorsesDelegate: true}, {Name: "b"}}},
+		{"top of given list", []ns.NationName{"x", "b"}, 1, []violators.Violator{{Name: "x", Over: 1, Tier: "standard", Cap: 2, Endorsements: 3, EndorsesDelegate: true}}},
+		{"empty given list", []ns.NationName{}, 0, []violators.Violator{}},
+		{"long given list is kept whole", many, 0, manyWant},
+	}
+
+	server := nstest.NewServer(t, region)
+
+	for kind, src := range server.Sources() {
+		for _, test := range tests {
+			args := Args{
+				Citizens:  citizens.None{},
+				Region:    region.Name,
+				Delegate:  ns.NationName(region.Delegate),
+				Policy:    policy.Standard(2, 2, 2),
+				Violators: test.violators,
+				Top:       test.top,
+			}
+
+			got, err := findViolators(src, graph.New(src, region.Name), args)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", kind, test.name, err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s/%s: findViolators() = %v, want %v", kind, test.name, got, test.want)
+			}
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	// a is 2 over a cap of 8 and x is 3 over a cap of 4, so each way of
+	// scoring ranks their endorsers differently.
+	shared := []violators.Violator{{Name: "a", Over: 2, Cap: 8}, {Name: "x", Over: 3, Cap: 4}}
+
+	tests := []struct {
+		name      string
+		violators []violators.Violator
+		score     string
+		want      map[ns.NationName]Endorser
+	}{
+		{
+			name:      "one violator",
+			violators: []violators.Violator{{Name: "x", Over: 1}},
+			score:     Equal,
+			want: map[ns.NationName]Endorser{
+				"e2": {"e2", 100, 1, []ns.NationName{"x"}},
+				"e3": {"e3", 100, 1, []ns.NationName{"x"}},
+				"e7": {"e7", 100, 1, []ns.NationName{"x"}},
+			},
+		},
+		{
+			name:      "shared endorsers",
+			violators: shared,
+			score:     Equal,
+			want: map[ns.NationName]Endorser{
+				"e1": {"e1", 50, 2, []ns.NationName{"a"}},
+				"e2": {"e2", 100, 5, []ns.NationName{"a", "x"}},
+				"e3": {"e3", 100, 5, []ns.NationName{"a", "x"}},
+				"e4": {"e4", 50, 2, []ns.NationName{"a"}},
+				"e5": {"e5", 50, 2, []ns.NationName{"a"}},
+				"e6": {"e6", 50, 2, []ns.NationName{"a"}},
+				"e7": {"e7", 50, 3, []ns.NationName{"x"}},
+			},
+		},
+		{
+			name:      "weighted by overage",
+			violators: shared,
+			score:     Overage,
+			want: map[ns.NationName]Endorser{
+				"e1": {"e1", 40, 2, []ns.NationName{"a"}},
+				"e2": {"e2", 100, 5, []ns.NationName{"a", "x"}},
+				"e3": {"e3", 100, 5, []ns.NationName{"a", "x"}},
+				"e4": {"e4", 40, 2, []ns.NationName{"a"}},
+				"e5": {"e5", 40, 2, []ns.NationName{"a"}},
+				"e6": {"e6", 40, 2, []ns.NationName{"a"}},
+				"e7": {"e7", 60, 3, []ns.NationName{"x"}},
+			},
+		},
+		{
+			name:      "relative to cap",
+			violators: shared,
+			score:     Relative,
+			want: map[ns.NationName]Endorser{
+				"e1": {"e1", 25, 2, []ns.NationName{"a"}},
+				"e2": {"e2", 100, 5, []ns.NationName{"a", "x"}},
+				"e3": {"e3", 100, 5, []ns.NationName{"a", "x"}},
+				"e4": {"e4", 25, 2, []ns.NationName{"a"}},
+				"e5": {"e5", 25, 2, []ns.NationName{"a"}},
+				"e6": {"e6", 25, 2, []ns.NationName{"a"}},
+				"e7": {"e7", 75, 3, []ns.NationName{"x"}},
+			},
+		},
+		{
+			name:      "raw count",
+			violators: shared,
+			score:     Count,
+			want: map[ns.NationName]Endorser{
+				"e1": {"e1", 1, 2, []ns.NationName{"a"}},
+				"e2": {"e2", 2, 5, []ns.NationName{"a", "x"}},
+				"e3": {"e3", 2, 5, []ns.NationName{"a", "x"}},
+				"e4": {"e4", 1, 2, []ns.NationName{"a"}},
+				"e5": {"e5", 1, 2, []ns.NationName{"a"}},
+				"e6": {"e6", 1, 2, []ns.NationName{"a"}},
+				"e7": {"e7", 1, 3, []ns.NationName{"x"}},
+			},
+		},
+		{
+			name:      "no overage to weigh",
+			violators: []violators.Violator{{Name: "x"}},
+			score:     Overage,
+			want: map[ns.NationName]Endorser{
+				"e2": {"e2", 0, 0, []ns.NationName{"x"}},
+				"e3": {"e3", 0, 0, []ns.NationName{"x"}},
+				"e7": {"e7", 0, 0, []ns.NationName{"x"}},
+			},
+		},
+	}
+
+	server := nstest.NewServer(t, region)
+
+	for kind, src := range server.Sources() {
+		for _, test := range tests {
+			endorsers, err := Aggregate(test.violators, graph.New(src, region.Name).EndorsersOf, test.score)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", kind, test.name, err)
+			}
+
+			for i := 1; i < len(endorsers); i++ {
+				if endorsers[i].Score > endorsers[i-1].Score {
+					t.Errorf("%s/%s: endorsers not sorted by score: %v", kind, test.name, endorsers)
+					break
+				}
+			}
+
+			got := make(map[ns.NationName]Endorser)
+			for _, endorser := range endorsers {
+				ns.SortNames(endorser.Endorsing)
+				got[endorser.Name] = endorser
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s/%s: Aggregate() = %v, want %v", kind, test.name, got, test.want)
+			}
+		}
+	}
+}
+
+func TestWeight(t *testing.T) {
+	tests := []struct {
+		name  string
+		score string
+		v     violators.Violator
+		want  float64
+	}{
+		{"equal", Equal, violators.Violator{Over: 6, Cap: 4}, 1},
+		{"count", Count, violators.Violator{Over: 6, Cap: 4}, 1},
+		{"overage", Overage, violators.Violator{Over: 6, Cap: 4}, 6},
+		{"relative", Relative, violators.Violator{Over: 6, Cap: 4}, 1.5},
+		{"relative to a cap of 0 counts it as 1", Relative, violators.Violator{Over: 3, Cap: 0}, 3},
+		{"relative to a cap of 1", Relative, violators.Violator{Over: 3, Cap: 1}, 3},
+		{"relative, no longer over", Relative, violators.Violator{Cap: 0}, 0},
+	}
+
+	for _, test := range tests {
+		if got := weight(test.score, test.v); got != test.want {
+			t.Errorf("%s: weight() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestAggregateEdgeCases(t *testing.T) {
+	endorsersOf := func(nation ns.NationName) ([]ns.NationName, error) {
+		switch nation {
+		case "a":
+			return []ns.NationName{"", "e1", ""}, nil
+		case "x":
+			return []ns.NationName{""}, nil
+		default:
+			return nil, nil
+		}
+	}
+
+	tests := []struct {
+		name      string
+		violators []violators.Violator
+		want      []Endorser
+	}{
+		{"nil violators", nil, []Endorser{}},
+		{"no violators", []violators.Violator{}, []Endorser{}},
+		{"no endorsers", []violators.Violator{{Name: "y", Over: 2}}, []Endorser{}},
+		{"blank endorsers", []violators.Violator{{Name: "a", Over: 2}, {Name: "x", Over: 1}}, []Endorser{{"e1", 50, 2, []ns.NationName{"a"}}}},
+		{"blank violator", []violators.Violator{{Name: ""}, {Name: "a", Over: 2}}, []Endorser{{"e1", 100, 2, []ns.NationName{"a"}}}},
+	}
+
+	for _, test := range tests {
+		for _, score := range []string{Equal, Overage, Relative, Count} {
+			got, err := Aggregate(test.violators, endorsersOf, score)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", test.name, score, err)
+			}
+
+			if got == nil {
+				t.Errorf("%s/%s: Aggregate() = nil, want an empty slice", test.name, score)
+			}
+
+			if score == Equal && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s: Aggregate() = %v, want %v", test.name, got, test.want)
+			}
+
+			for _, endorser := range got {
+				if math.IsNaN(endorser.Score) || math.IsInf(endorser.Score, 0) {
+					t.Errorf("%s/%s: %s has score %v", test.name, score, endorser.Name, endorser.Score)
+				}
+			}
+		}
+	}
+}
+
+func TestBuildReportWithoutViolators(t *testing.T) {
+	r := buildReport(Args{Score: Equal}, nil, []Endorser{})
+
+	table := r.Tables[0]
+	if len(table.Rows) != 0 {
+		t.Errorf("buildReport() rows = %v, want none", table.Rows)
+	}
+
+	if table.Empty == "" {
+		t.Error("buildReport() says nothing when there are no violators")
+	}
+}
+
+func TestAggregateUnknownNation(t *testing.T) {
+	server := nstest.NewServer(t, region)
+
+	_, err := Aggregate([]violators.Violator{{Name: "nobody", Over: 1}}, graph.New(server.APISource(), region.Name).EndorsersOf, Equal)
+	if !errors.Is(err, ns.ErrNationNotFound) {
+		t.Errorf("Aggregate() error = %v, want ErrNationNotFound", err)
+	}
+}
@@ -0,0 +1,137 @@
+// Package citizens reads a region's list of citizen nations from wherever the
+// region keeps it.
+package citizens
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+
+	"rsc-tools/internal/ns"
+)
+
+// Source provides the names of a region's citizen nations, in canonical
+// form whatever the list's own spelling.
+type Source interface {
+	Citizens() ([]ns.NationName, error)
+}
+
+// Sheet reads citizens from one column of a Google Sheet.
+type Sheet struct {
+	Key           string
+	SpreadsheetID string
+	Range         string
+}
+
+func (s Sheet) Citizens() ([]ns.NationName, error) {
+	ctx := context.Background()
+
+	service, err := sheets.NewService(ctx, option.WithAPIKey(s.Key))
+	if err != nil {
+		return nil, fmt.Errorf("creating Sheets service: %w", err)
+	}
+
+	response, err := service.Spreadsheets.Values.Get(s.SpreadsheetID, s.Range).Do()
+	if err != nil {
+		return nil, fmt.Errorf("reading citizens from spreadsheet %s: %w", s.SpreadsheetID, err)
+	}
+
+	var data []ns.NationName
+	for _, row := range response.Values {
+		if len(row) == 0 {
+			continue
+		}
+		if name, ok := row[0].(string); ok && ns.Canonical(name) != "" {
+			data = append(data, ns.Canonical(name))
+		}
+	}
+
+	return data, nil
+}
+
+// File reads citizens from a local text file with one nation per line, or
+// from the first column of a CSV file.
+type File struct {
+	Path string
+}
+
+func (f File) Citizens() ([]ns.NationName, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading citizens: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var data []ns.NationName
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading citizens from %s: %w", f.Path, err)
+		}
+
+		if name := ns.Canonical(record[0]); name != "" {
+			data = append(data, name)
+		}
+	}
+
+	return data, nil
+}
+
+// URL reads citizens from a web page serving one nation per line.
+type URL struct {
+	URL    string
+	Client *http.Client
+}
+
+func (u URL) Citizens() ([]ns.NationName, error) {
+	client := u.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Get(u.URL)
+	if err != nil {
+		return nil, fmt.Errorf("reading citizens: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading citizens from %s: HTTP %d", u.URL, response.StatusCode)
+	}
+
+	var data []ns.NationName
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		if name := ns.Canonical(scanner.Text()); name != "" {
+			data = append(data, name)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading citizens from %s: %w", u.URL, err)
+	}
+
+	return data, nil
+}
+
+// None is used when no citizen list is configured; nobody is a citizen.
+type None struct{}
+
+func (None) Citizens() ([]ns.NationName, error) {
+	return nil, nil
+}
@@ -0,0 +1,49 @@
+package citizens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"rsc-tools/internal/ns"
+)
+
+// list spells citizens the way a roll kept by hand might.
+const list = "Upc\nLe Libertia\n\n  mancheseva_city  \n"
+
+var want = []ns.NationName{"upc", "le_libertia", "mancheseva_city"}
+
+func TestFileCanonicalizes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "citizens.txt")
+	if err := os.WriteFile(path, []byte(list), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := File{Path: path}.Citizens()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Citizens() = %q, want %q", got, want)
+	}
+}
+
+func TestURLCanonicalizes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(list))
+	}))
+	defer server.Close()
+
+	got, err := URL{URL: server.URL, Client: server.Client()}.Citizens()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Citizens() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+// Package config loads the region's endocap policy and the user's settings
+// from an rsc.toml file.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/policy"
+)
+
+// FileName is the name of the config file looked for next to the rsc binary.
+const FileName = "rsc.toml"
+
+// Config holds the tools' settings. Nation names are canonicalized as they are
+// read, so they can be written as they appear on the site.
+type Config struct {
+	User     ns.NationName   `toml:"user"`
+	Key      string          `toml:"key"`
+	Citizens Citizens        `toml:"citizens"`
+	Region   string          `toml:"region"`
+	Delegate ns.NationName   `toml:"delegate"`
+	Excluded []ns.NationName `toml:"excluded"`
+	Base     int             `toml:"base"`
+	Standard int             `toml:"standard"`
+	Citizen  int             `toml:"citizen"`
+	Limit    int             `toml:"limit"`
+	CacheDir string          `toml:"cache_dir"`
+
+	// Tiers replaces the base, standard and citizen caps for regions whose
+	// endocap law has a different shape.
+	Tiers []policy.Tier `toml:"tier"`
+}
+
+// Citizens says where the region's citizen list is kept. File and URL take
+// precedence over the Google Sheet, which is only read when a key is set.
+type Citizens struct {
+	Sheet string `toml:"sheet"`
+	Range string `toml:"range"`
+	File  string `toml:"file"`
+	URL   string `toml:"url"`
+}
+
+// Default returns the settings used when neither the config file nor the
+// command line provides a value.
+func Default() Config {
+	return Config{
+		Region: "europeia",
+		Citizens: Citizens{
+			Sheet: "1Zi2HtQuykoWV2P36B61J_eBnhSgj3VyDWFUbtbYWyTo",
+			Range: "Citizens!C2:C",
+		},
+		Base:     10,
+		Standard: 25,
+		Citizen:  50,
+		Limit:    5,
+	}
+}
+
+// Load reads the config file at path on top of the defaults. If path is empty,
+// rsc.toml next to the running binary is used when it exists, and the defaults
+// otherwise.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = besideExecutable()
+		if path == "" {
+			return cfg, nil
+		}
+	}
+
+	meta, err := toml.DecodeFile(path, &cfg)
+	if err != nil {
+		return cfg, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return cfg, fmt.Errorf("reading config %s: unknown setting %q", path, undecoded[0].String())
+	}
+
+	return cfg, nil
+}
+
+func besideExecutable() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+
+	path := filepath.Join(filepath.Dir(exe), FileName)
+
+	_, err = os.Stat(path)
+	if err != nil {
+		return ""
+	}
+
+	return path
+}
+
+// Policy returns the endocap policy: the configured tiers if there are any,
+// and the three-tier base, standard and citizen policy otherwise.
+func (c Config) Policy() (policy.Policy, error) {
+	p := policy.Standard(c.Base, c.Standard, c.Citizen)
+	if len(c.Tiers) > 0 {
+		p = policy.Policy{Tiers: c.Tiers}
+	}
+
+	err := p.Validate()
+	if err != nil {
+		return p, fmt.Errorf("endocap policy: %w", err)
+	}
+
+	return p, nil
+}
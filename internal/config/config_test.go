@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/policy"
+	"rsc-tools/internal/set"
+)
+
+// write saves contents as a config file and returns its path.
+func write(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     func(*Config)
+	}{
+		{
+			name: "empty file keeps the defaults",
+			want: func(*Config) {},
+		},
+		{
+			name: "settings replace the defaults",
+			contents: `region = "the_north_pacific"
+base = 5
+limit = 2
+cache_dir = "dumps"
+
+[citizens]
+file = "citizens.txt"
+`,
+			want: func(c *Config) {
+				c.Region = "the_north_pacific"
+				c.Base = 5
+				c.Limit = 2
+				c.CacheDir = "dumps"
+				c.Citizens.File = "citizens.txt"
+			},
+		},
+		{
+			name: "names are canonicalized",
+			contents: `user = "Le Libertia"
+delegate = "UPC"
+excluded = ["Pland Adanna", " pichtonia "]
+`,
+			want: func(c *Config) {
+				c.User = "le_libertia"
+				c.Delegate = "upc"
+				c.Excluded = []ns.NationName{"pland_adanna", "pichtonia"}
+			},
+		},
+		{
+			name: "tiers are read in order",
+			contents: `[[tier]]
+name = "honoured"
+cap = 75
+require = ["whitelisted"]
+whitelist = ["Le Libertia"]
+
+[[tier]]
+name = "base"
+cap = 10
+`,
+			want: func(c *Config) {
+				c.Tiers = []policy.Tier{
+					{Name: "honoured", Cap: 75, Require: []string{policy.Whitelisted}, Whitelist: set.New("le_libertia")},
+					{Name: "base", Cap: 10},
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := Load(write(t, test.contents))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		want := Default()
+		test.want(&want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Load() = %+v, want %+v", test.name, got, want)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		setting  string
+	}{
+		{"top level", "regoin = \"europeia\"\n", "regoin"},
+		{"citizens", "[citizens]\nsheeet = \"x\"\n", "citizens.sheeet"},
+		{"tier", "[[tier]]\nname = \"base\"\ncaps = 10\n", "tier.caps"},
+	}
+
+	for _, test := range tests {
+		_, err := Load(write(t, test.contents))
+		if err == nil || !strings.Contains(err.Error(), test.setting) {
+			t.Errorf("%s: Load() error = %v, want one naming %q", test.name, err, test.setting)
+		}
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+	cfg, err := Load(filepath.Join("..", "..", "scripts", FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cfg.Policy(); err != nil {
+		t.Error(err)
+	}
+}
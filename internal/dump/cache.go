@@ -0,0 +1,123 @@
+package dump
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"rsc-tools/internal/ns"
+)
+
+// Cache keeps downloaded daily dumps in Dir, named by the date they were
+// generated, so that every run on the same day shares one download.
+type Cache struct {
+	Dir    string
+	Client *ns.Client
+}
+
+// DefaultDir returns the directory dumps are cached in when none is
+// configured.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("finding the cache directory: %w", err)
+	}
+
+	return filepath.Join(dir, "rsc-tools"), nil
+}
+
+// Open returns the named gzipped dump ("nations" or "regions"). A cached copy
+// generated today is used as is; an older one is only replaced if the server
+// has a newer dump.
+func (c Cache) Open(name string) (*os.File, error) {
+	err := os.MkdirAll(c.Dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("creating the dump cache: %w", err)
+	}
+
+	cached, modified := c.latest(name)
+	if cached != "" && sameDay(modified, time.Now()) {
+		return os.Open(cached)
+	}
+
+	fmt.Fprintf(os.Stderr, "Checking for a new %s dump\n", name)
+
+	body, modified, err := c.Client.DailyDump(name, modified)
+	if errors.Is(err, ns.ErrNotModified) {
+		return os.Open(cached)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("downloading the %s dump: %w", name, err)
+	}
+	defer body.Close()
+
+	path, err := c.store(name, body, modified)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != "" && cached != path {
+		os.Remove(cached)
+	}
+
+	return os.Open(path)
+}
+
+// latest returns the newest cached copy of the named dump and when it was
+// generated, or an empty path if there is none.
+func (c Cache) latest(name string) (string, time.Time) {
+	matches, err := filepath.Glob(filepath.Join(c.Dir, name+"-*.xml.gz"))
+	if err != nil || len(matches) == 0 {
+		return "", time.Time{}
+	}
+
+	sort.Strings(matches)
+	path := matches[len(matches)-1]
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", time.Time{}
+	}
+
+	return path, info.ModTime()
+}
+
+// store writes a downloaded dump into the cache, stamped with the time it was
+// generated.
+func (c Cache) store(name string, body io.Reader, modified time.Time) (string, error) {
+	path := filepath.Join(c.Dir, fmt.Sprintf("%s-%s.xml.gz", name, modified.UTC().Format("2006-01-02")))
+
+	tmp, err := os.CreateTemp(c.Dir, name+"-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("caching the %s dump: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("caching the %s dump: %w", name, err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return "", fmt.Errorf("caching the %s dump: %w", name, err)
+	}
+
+	err = os.Chtimes(path, modified, modified)
+	if err != nil {
+		return "", fmt.Errorf("caching the %s dump: %w", name, err)
+	}
+
+	return path, nil
+}
+
+func sameDay(a time.Time, b time.Time) bool {
+	return a.UTC().Format("2006-01-02") == b.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,186 @@
+package dump_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"rsc-tools/internal/dump"
+	"rsc-tools/internal/nstest"
+)
+
+// cached is what a dump already in the cache holds, so tests can tell it from
+// a fresh download.
+const cached = "cached"
+
+func TestCacheOpen(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1)
+	lastWeek := now.AddDate(0, 0, -7)
+
+	tests := []struct {
+		name string
+		// modified is when the server's dump was generated; zero sends no
+		// Last-Modified.
+		modified time.Time
+		// cachedAt, if not zero, is when the dump already in the cache was
+		// generated.
+		cachedAt     time.Time
+		wantRequests int
+		wantFiles    []string
+		wantCached   bool
+		wantModified time.Time
+	}{
+		{
+			name:         "same-day dump is reused",
+			modified:     yesterday,
+			cachedAt:     now,
+			wantRequests: 0,
+			wantFiles:    []string{"nations-" + today + ".xml.gz"},
+			wantCached:   true,
+			wantModified: now,
+		},
+		{
+			name:         "unchanged dump is not downloaded again",
+			modified:     lastWeek,
+			cachedAt:     lastWeek,
+			wantRequests: 1,
+			wantFiles:    []string{"nations-" + lastWeek.Format("2006-01-02") + ".xml.gz"},
+			wantCached:   true,
+			wantModified: lastWeek,
+		},
+		{
+			name:         "newer dump replaces older",
+			modified:     yesterday,
+			cachedAt:     lastWeek,
+			wantRequests: 1,
+			wantFiles:    []string{"nations-" + yesterday.Format("2006-01-02") + ".xml.gz"},
+			wantModified: yesterday,
+		},
+		{
+			name:         "empty cache downloads",
+			modified:     yesterday,
+			wantRequests: 1,
+			wantFiles:    []string{"nations-" + yesterday.Format("2006-01-02") + ".xml.gz"},
+			wantModified: yesterday,
+		},
+		{
+			name:         "no Last-Modified is stamped with now",
+			cachedAt:     lastWeek,
+			wantRequests: 1,
+			wantFiles:    []string{"nations-" + today + ".xml.gz"},
+		},
+	}
+
+	for _, test := range tests {
+		server := nstest.NewServer(t, nstest.Region{Name: "europeia", Nations: []nstest.Nation{{Name: "a", WA: true}}})
+		server.DumpModified = test.modified
+
+		cache := dump.Cache{Dir: t.TempDir(), Client: server.Client()}
+		if !test.cachedAt.IsZero() {
+			path := filepath.Join(cache.Dir, "nations-"+test.cachedAt.Format("2006-01-02")+".xml.gz")
+			if err := os.WriteFile(path, []byte(cached), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(path, test.cachedAt, test.cachedAt); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		start := time.Now()
+		f, err := cache.Open("nations")
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		body, err := readDump(f)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if got := len(server.Requests()); got != test.wantRequests {
+			t.Errorf("%s: %d requests, want %d", test.name, got, test.wantRequests)
+		}
+
+		if got := files(t, cache.Dir); !reflect.DeepEqual(got, test.wantFiles) {
+			t.Errorf("%s: cache holds %v, want %v", test.name, got, test.wantFiles)
+		}
+
+		if test.wantCached && body != cached {
+			t.Errorf("%s: got a new download, want the cached dump", test.name)
+		}
+		if !test.wantCached && !strings.Contains(body, "<NATIONS") {
+			t.Errorf("%s: got %q, want a new download", test.name, body)
+		}
+
+		info, err := os.Stat(filepath.Join(cache.Dir, test.wantFiles[0]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case !test.wantModified.IsZero() && !info.ModTime().Equal(test.wantModified):
+			t.Errorf("%s: cached dump stamped %v, want %v", test.name, info.ModTime(), test.wantModified)
+		case test.wantModified.IsZero() && info.ModTime().Before(start.Truncate(time.Second)):
+			t.Errorf("%s: cached dump stamped %v, want the time it was downloaded", test.name, info.ModTime())
+		}
+	}
+}
+
+func TestCacheOpenTwiceDownloadsOnce(t *testing.T) {
+	server := nstest.NewServer(t, nstest.Region{Name: "europeia"})
+	server.DumpModified = time.Now()
+
+	cache := dump.Cache{Dir: t.TempDir(), Client: server.Client()}
+	for i := 0; i < 2; i++ {
+		f, err := cache.Open("regions")
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+
+	if got := server.Requests(); len(got) != 1 {
+		t.Errorf("requests = %v, want one download", got)
+	}
+}
+
+// readDump reads and closes f, unzipping it unless it is a stand-in written
+// by the test.
+func readDump(f *os.File) (string, error) {
+	defer f.Close()
+
+	raw, err := io.ReadAll(f)
+	if err != nil || string(raw) == cached {
+		return string(raw), err
+	}
+
+	gz, err := gzip.NewReader(strings.NewReader(string(raw)))
+	if err != nil {
+		return "", err
+	}
+	body, err := io.ReadAll(gz)
+
+	return string(body), err
+}
+
+// files returns the names of the files in dir.
+func files(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	return names
+}
@@ -0,0 +1,88 @@
+// Package dump reads the NationStates daily data dumps as a stream, without
+// extracting them to disk or holding them in memory.
+package dump
+
+import (
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"rsc-tools/internal/ns"
+)
+
+// Nation is a nation's entry in the nations.xml.gz daily dump.
+type Nation struct {
+	Name         ns.NationName `xml:"NAME"`
+	Region       string        `xml:"REGION"`
+	WAStatus     string        `xml:"UNSTATUS"`
+	Endorsements string        `xml:"ENDORSEMENTS"`
+}
+
+// EndorsementList returns the nations endorsing n.
+func (n Nation) EndorsementList() []ns.NationName {
+	return ns.ParseNames(n.Endorsements, ",")
+}
+
+// InWA reports whether the nation is a member of the World Assembly.
+func (n Nation) InWA() bool {
+	return n.WAStatus == "WA Member" || n.WAStatus == "WA Delegate"
+}
+
+// Nations decodes nations one at a time from a gzipped nations dump.
+type Nations struct {
+	gz      *gzip.Reader
+	decoder *xml.Decoder
+	region  ns.NationName
+}
+
+// NewNations starts reading the gzipped nations dump r. If region is not
+// empty, only nations in that region are returned.
+func NewNations(r io.Reader, region string) (*Nations, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("opening the nations dump: %w", err)
+	}
+
+	return &Nations{
+		gz:      gz,
+		decoder: xml.NewDecoder(gz),
+		region:  ns.Canonical(region),
+	}, nil
+}
+
+// Next returns the next nation in the dump, or io.EOF when there are none
+// left.
+func (n *Nations) Next() (Nation, error) {
+	for {
+		token, err := n.decoder.Token()
+		if err == io.EOF {
+			return Nation{}, io.EOF
+		}
+		if err != nil {
+			return Nation{}, fmt.Errorf("reading the nations dump: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "NATION" {
+			continue
+		}
+
+		var nation Nation
+		err = n.decoder.DecodeElement(&nation, &start)
+		if err != nil {
+			return Nation{}, fmt.Errorf("reading the nations dump: %w", err)
+		}
+
+		if n.region != "" && ns.Canonical(nation.Region) != n.region {
+			continue
+		}
+
+		return nation, nil
+	}
+}
+
+// Close releases the gzip stream. It does not close the underlying reader.
+func (n *Nations) Close() error {
+	return n.gz.Close()
+}
@@ -0,0 +1,81 @@
+package dump
+
+import (
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"rsc-tools/internal/ns"
+)
+
+// Region is a region's entry in the regions.xml.gz daily dump.
+type Region struct {
+	Name     string        `xml:"NAME"`
+	Nations  string        `xml:"NATIONS"`
+	Delegate ns.NationName `xml:"DELEGATE"`
+	Officers []ns.Officer  `xml:"OFFICERS>OFFICER"`
+}
+
+// NationList returns the nations residing in the region.
+func (r Region) NationList() []ns.NationName {
+	return ns.ParseNames(r.Nations, ":")
+}
+
+// DelegateName returns the region's WA delegate, or an empty string if it has
+// none.
+func (r Region) DelegateName() ns.NationName {
+	if r.Delegate == "0" {
+		return ""
+	}
+
+	return r.Delegate
+}
+
+// OfficerList returns the nations holding a regional office.
+func (r Region) OfficerList() []ns.NationName {
+	officers := make([]ns.NationName, 0, len(r.Officers))
+	for _, officer := range r.Officers {
+		officers = append(officers, officer.Nation)
+	}
+
+	return officers
+}
+
+// FindRegion streams the gzipped regions dump r and returns the entry for the
+// named region.
+func FindRegion(r io.Reader, name string) (Region, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return Region{}, fmt.Errorf("opening the regions dump: %w", err)
+	}
+	defer gz.Close()
+
+	canonical := ns.Canonical(name)
+	decoder := xml.NewDecoder(gz)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return Region{}, &ns.Error{Endpoint: "region", Name: name, Err: ns.ErrRegionNotFound}
+		}
+		if err != nil {
+			return Region{}, fmt.Errorf("reading the regions dump: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "REGION" {
+			continue
+		}
+
+		var region Region
+		err = decoder.DecodeElement(&region, &start)
+		if err != nil {
+			return Region{}, fmt.Errorf("reading the regions dump: %w", err)
+		}
+
+		if ns.Canonical(region.Name) == canonical {
+			return region, nil
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Package graph models who is endorsing whom in a region, so that every tool
+// asks the same questions of the same data.
+package graph
+
+import (
+	"fmt"
+
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/set"
+	"rsc-tools/internal/source"
+)
+
+// EndorsementGraph is the directed graph of endorsements in a region: an edge
+// runs from each endorser to the nation it endorses. Nothing is read from the
+// source until it is asked for, and each part is read at most once, since
+// with the API source every nation's endorsements cost a request.
+type EndorsementGraph struct {
+	Region string
+
+	src source.Source
+
+	ranked []ns.NationName
+	counts map[ns.NationName]int
+
+	wa    []ns.NationName
+	waSet set.Set
+
+	endorsers map[ns.NationName][]ns.NationName
+	endorsing map[ns.NationName][]ns.NationName
+}
+
+// New returns the endorsement graph of region, read from src.
+func New(src source.Source, region string) *EndorsementGraph {
+	return &EndorsementGraph{
+		Region:    region,
+		src:       src,
+		endorsers: make(map[ns.NationName][]ns.NationName),
+	}
+}
+
+// Nations returns the nations with at least one endorsement, most endorsed
+// first.
+func (g *EndorsementGraph) Nations() ([]ns.NationName, error) {
+	err := g.loadCounts()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.ranked, nil
+}
+
+// InDegree returns the number of nations endorsing nation.
+func (g *EndorsementGraph) InDegree(nation ns.NationName) (int, error) {
+	if endorsers, ok := g.endorsers[nation]; ok {
+		return len(endorsers), nil
+	}
+
+	err := g.loadCounts()
+	if err != nil {
+		return 0, err
+	}
+
+	return g.counts[nation], nil
+}
+
+// EndorsersOf returns the nations endorsing nation.
+func (g *EndorsementGraph) EndorsersOf(nation ns.NationName) ([]ns.NationName, error) {
+	if endorsers, ok := g.endorsers[nation]; ok {
+		return endorsers, nil
+	}
+
+	endorsers, err := g.src.Endorsements(nation)
+	if err != nil {
+		return nil, err
+	}
+
+	g.endorsers[nation] = endorsers
+	return endorsers, nil
+}
+
+// EndorsedBy returns the nations in the region that nation is endorsing. The
+// first call reads every endorsement in the region, which with the API source
+// takes one request per endorsed nation.
+func (g *EndorsementGraph) EndorsedBy(nation ns.NationName) ([]ns.NationName, error) {
+	err := g.loadEdges()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.endorsing[nation], nil
+}
+
+// OutDegree returns the number of nations in the region that nation is
+// endorsing. Like EndorsedBy, it reads every endorsement in the region.
+func (g *EndorsementGraph) OutDegree(nation ns.NationName) (int, error) {
+	endorsing, err := g.EndorsedBy(nation)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(endorsing), nil
+}
+
+// WANations returns the region's World Assembly members.
+func (g *EndorsementGraph) WANations() ([]ns.NationName, error) {
+	err := g.loadWA()
+	if err != nil {
+		return nil, err
+	}
+
+	return g.wa, nil
+}
+
+// InWA reports whether nation is a member of the World Assembly.
+func (g *EndorsementGraph) InWA(nation ns.NationName) (bool, error) {
+	err := g.loadWA()
+	if err != nil {
+		return false, err
+	}
+
+	return g.waSet.Has(nation), nil
+}
+
+func (g *EndorsementGraph) loadCounts() error {
+	if g.counts != nil {
+		return nil
+	}
+
+	nations, err := g.src.EndorsementCounts()
+	if err != nil {
+		return fmt.Errorf("getting endorsement numbers: %w", err)
+	}
+
+	g.counts = make(map[ns.NationName]int, len(nations))
+	g.ranked = make([]ns.NationName, 0, len(nations))
+	for _, nation := range nations {
+		g.counts[nation.Name] = nation.Score
+		g.ranked = append(g.ranked, nation.Name)
+	}
+
+	return nil
+}
+
+func (g *EndorsementGraph) loadWA() error {
+	if g.waSet != nil {
+		return nil
+	}
+
+	wa, err := g.src.WANations()
+	if err != nil {
+		return fmt.Errorf("getting WA nations: %w", err)
+	}
+
+	g.wa = wa
+	g.waSet = set.New(wa...)
+
+	return nil
+}
+
+// loadEdges reads the endorsers of every endorsed nation and indexes the
+// edges by endorser.
+func (g *EndorsementGraph) loadEdges() error {
+	if g.endorsing != nil {
+		return nil
+	}
+
+	nations, err := g.Nations()
+	if err != nil {
+		return err
+	}
+
+	endorsing := make(map[ns.NationName][]ns.NationName)
+	for _, nation := range nations {
+		endorsers, err := g.EndorsersOf(nation)
+		if err != nil {
+			return fmt.Errorf("getting endorsements of %s: %w", nation, err)
+		}
+
+		for _, endorser := range endorsers {
+			endorsing[endorser] = append(endorsing[endorser], nation)
+		}
+	}
+
+	for _, endorsed := range endorsing {
+		ns.SortNames(endorsed)
+	}
+
+	g.endorsing = endorsing
+	return nil
+}
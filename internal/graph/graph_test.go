@@ -0,0 +1,70 @@
+package graph_test
+
+import (
+	"reflect"
+	"testing"
+
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/nstest"
+)
+
+// region has upc endorsing a and b, a and b endorsing each other, and c
+// outside the WA.
+var region = nstest.Region{
+	Name:     "europeia",
+	Delegate: "a",
+	Nations: []nstest.Nation{
+		{Name: "a", WA: true, Endorsements: []string{"b", "upc"}},
+		{Name: "b", WA: true, Endorsements: []string{"a", "upc"}},
+		{Name: "c", WA: false},
+		{Name: "upc", WA: true},
+	},
+}
+
+func TestEndorsementGraph(t *testing.T) {
+	server := nstest.NewServer(t, region)
+
+	for kind, src := range server.Sources() {
+		g := graph.New(src, region.Name)
+
+		nations, err := g.Nations()
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		if want := []ns.NationName{"a", "b"}; !reflect.DeepEqual(nations, want) {
+			t.Errorf("%s: Nations() = %v, want %v", kind, nations, want)
+		}
+
+		for nation, want := range map[ns.NationName]int{"a": 2, "b": 2, "c": 0, "upc": 0} {
+			got, err := g.InDegree(nation)
+			if err != nil || got != want {
+				t.Errorf("%s: InDegree(%s) = %d, %v, want %d", kind, nation, got, err, want)
+			}
+		}
+
+		endorsers, err := g.EndorsersOf("a")
+		if err != nil || !reflect.DeepEqual(endorsers, []ns.NationName{"b", "upc"}) {
+			t.Errorf("%s: EndorsersOf(a) = %v, %v, want [b upc]", kind, endorsers, err)
+		}
+
+		endorsing, err := g.EndorsedBy("upc")
+		if err != nil || !reflect.DeepEqual(endorsing, []ns.NationName{"a", "b"}) {
+			t.Errorf("%s: EndorsedBy(upc) = %v, %v, want [a b]", kind, endorsing, err)
+		}
+
+		for nation, want := range map[ns.NationName]int{"a": 1, "b": 1, "c": 0, "upc": 2} {
+			got, err := g.OutDegree(nation)
+			if err != nil || got != want {
+				t.Errorf("%s: OutDegree(%s) = %d, %v, want %d", kind, nation, got, err, want)
+			}
+		}
+
+		for nation, want := range map[ns.NationName]bool{"a": true, "c": false, "upc": true} {
+			got, err := g.InWA(nation)
+			if err != nil || got != want {
+				t.Errorf("%s: InWA(%s) = %v, %v, want %v", kind, nation, got, err, want)
+			}
+		}
+	}
+}
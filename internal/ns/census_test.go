@@ -0,0 +1,53 @@
+package ns_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/nstest"
+)
+
+func TestCensusRanksPaginates(t *testing.T) {
+	// 45 nations endorsed by 45 down to 1 nations, and 5 with none, so the
+	// ranking spans three pages and stops at the first score of zero.
+	region := nstest.Region{Name: "europeia"}
+	for i := 0; i < 50; i++ {
+		nation := nstest.Nation{Name: fmt.Sprintf("nation_%02d", i), WA: true}
+		if i < 45 {
+			nation.Endorsements = nstest.Names("endorser", 45-i)
+		}
+		region.Nations = append(region.Nations, nation)
+	}
+
+	server := nstest.NewServer(t, region)
+
+	nations, err := server.Client().CensusRanks("europeia", ns.EndorsementsScale)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(nations) != 45 {
+		t.Fatalf("got %d nations, want 45", len(nations))
+	}
+
+	for i, nation := range nations {
+		if nation.Rank != i+1 || nation.Score != 45-i {
+			t.Errorf("nations[%d] = %+v, want rank %d and score %d", i, nation, i+1, 45-i)
+		}
+	}
+
+	if requests := len(server.Requests()); requests != 3 {
+		t.Errorf("made %d requests, want 3", requests)
+	}
+}
+
+func TestCensusRanksUnknownRegion(t *testing.T) {
+	server := nstest.NewServer(t, nstest.Region{Name: "europeia"})
+
+	_, err := server.Client().CensusRanks("nowhere", ns.EndorsementsScale)
+	if !errors.Is(err, ns.ErrRegionNotFound) {
+		t.Errorf("CensusRanks() error = %v, want ErrRegionNotFound", err)
+	}
+}
@@ -0,0 +1,147 @@
+// Package ns is a small client for the NationStates API shared by the
+// rsc-tools commands.
+package ns
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the root of the live NationStates site.
+const DefaultBaseURL = "https://www.nationstates.net"
+
+// maxAttempts is the number of times an API request is tried before giving
+// up on a 429 response.
+const maxAttempts = 3
+
+// Client makes requests against the NationStates API. BaseURL and HTTPClient
+// may be replaced to point the client at a fake server. API requests are paced
+// by Limiter; a nil Limiter disables pacing.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	UserAgent  string
+	Limiter    *Limiter
+}
+
+// NewClient returns a Client for the live site identifying itself as tool,
+// run by the nation user.
+func NewClient(tool string, user string) *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		HTTPClient: &http.Client{},
+		UserAgent:  fmt.Sprintf("%s/1.0 (%s)", tool, user),
+		Limiter:    NewLimiter(),
+	}
+}
+
+func (c *Client) get(path string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest("GET", strings.TrimRight(c.BaseURL, "/")+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	response, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("making the API request: %w", err)
+	}
+
+	return response, nil
+}
+
+// api requests shards of the named nation or region and parses the XML
+// response into v, waiting and retrying when NationStates reports the rate
+// limit was hit.
+func (c *Client) api(endpoint string, name string, shards string, v interface{}) error {
+	var response *http.Response
+	for attempt := 1; ; attempt++ {
+		c.Limiter.Wait()
+
+		var err error
+		response, err = c.get(fmt.Sprintf("/cgi-bin/api.cgi?%s=%s&q=%s", endpoint, name, shards), nil)
+		if err != nil {
+			return &Error{Endpoint: endpoint, Name: name, Err: err}
+		}
+
+		c.Limiter.Update(response)
+
+		if response.StatusCode != http.StatusTooManyRequests {
+			break
+		}
+
+		response.Body.Close()
+
+		if attempt == maxAttempts {
+			return &Error{Endpoint: endpoint, Name: name, Status: response.StatusCode, Err: ErrRateLimited}
+		}
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return &Error{Endpoint: endpoint, Name: name, Status: response.StatusCode, Err: notFound(endpoint)}
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return &Error{Endpoint: endpoint, Name: name, Status: response.StatusCode, Err: fmt.Errorf("reading the response body: %w", err)}
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return &Error{Endpoint: endpoint, Name: name, Status: response.StatusCode, Err: errors.New(pageMessage(body))}
+	}
+
+	err = errorPage(body)
+	if err != nil {
+		return &Error{Endpoint: endpoint, Name: name, Status: response.StatusCode, Err: err}
+	}
+
+	err = xml.Unmarshal(body, v)
+	if err != nil {
+		return &Error{Endpoint: endpoint, Name: name, Status: response.StatusCode, Err: fmt.Errorf("parsing the XML response: %w", err)}
+	}
+
+	return nil
+}
+
+// DailyDump opens the named daily data dump ("nations" or "regions") and
+// reports when it was generated. If since is not zero and the dump has not
+// changed since then, DailyDump returns ErrNotModified. The caller must close
+// the returned gzip stream.
+func (c *Client) DailyDump(name string, since time.Time) (io.ReadCloser, time.Time, error) {
+	header := http.Header{}
+	if !since.IsZero() {
+		header.Set("If-Modified-Since", since.UTC().Format(http.TimeFormat))
+	}
+
+	response, err := c.get(fmt.Sprintf("/pages/%s.xml.gz", name), header)
+	if err != nil {
+		return nil, time.Time{}, &Error{Endpoint: "dump", Name: name, Err: err}
+	}
+
+	if response.StatusCode == http.StatusNotModified {
+		response.Body.Close()
+		return nil, since, ErrNotModified
+	}
+
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		return nil, time.Time{}, &Error{Endpoint: "dump", Name: name, Status: response.StatusCode, Err: errors.New(http.StatusText(response.StatusCode))}
+	}
+
+	modified, err := http.ParseTime(response.Header.Get("Last-Modified"))
+	if err != nil {
+		modified = time.Now()
+	}
+
+	return response.Body, modified, nil
+}
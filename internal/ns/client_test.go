@@ -0,0 +1,80 @@
+package ns
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAPIRetriesRateLimit(t *testing.T) {
+	body, err := os.ReadFile("testdata/region_wanations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantErr   bool
+		wantCalls int
+		wantSlept []time.Duration
+	}{
+		{
+			name:      "429 then 200",
+			statuses:  []int{http.StatusTooManyRequests, http.StatusOK},
+			wantCalls: 2,
+			wantSlept: []time.Duration{5 * time.Second},
+		},
+		{
+			name:      "429 every time",
+			statuses:  []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			wantErr:   true,
+			wantCalls: maxAttempts,
+			wantSlept: []time.Duration{5 * time.Second, 5 * time.Second},
+		},
+	}
+
+	for _, test := range tests {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status := test.statuses[calls]
+			calls++
+			if status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "5")
+			}
+			w.WriteHeader(status)
+			w.Write(body)
+		}))
+
+		clock := &fakeClock{now: time.Unix(0, 0)}
+		client := &Client{BaseURL: server.URL, HTTPClient: server.Client(), Limiter: clock.limiter()}
+
+		got, err := client.WANations("europeia")
+		server.Close()
+
+		if calls != test.wantCalls {
+			t.Errorf("%s: %d requests, want %d", test.name, calls, test.wantCalls)
+		}
+		if !reflect.DeepEqual(clock.slept, test.wantSlept) {
+			t.Errorf("%s: slept %v, want %v", test.name, clock.slept, test.wantSlept)
+		}
+
+		if !test.wantErr {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			} else if len(got) == 0 {
+				t.Errorf("%s: WANations() read no nations", test.name)
+			}
+			continue
+		}
+
+		var apiErr *Error
+		if !errors.Is(err, ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.Status != http.StatusTooManyRequests {
+			t.Errorf("%s: error = %v, want ErrRateLimited with status 429", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package ns
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrNationNotFound = errors.New("nation does not exist")
+	ErrRegionNotFound = errors.New("region does not exist")
+	ErrRateLimited    = errors.New("rate limited")
+
+	// ErrNotModified is returned by DailyDump when the dump has not changed
+	// since the given time.
+	ErrNotModified = errors.New("not modified")
+)
+
+// Error describes a failed request for a nation, region or dump. It wraps the
+// underlying cause, which may be one of the sentinel errors above.
+type Error struct {
+	Endpoint string
+	Name     string
+	Status   int
+	Err      error
+}
+
+func (e *Error) Error() string {
+	target := e.Endpoint
+	if e.Name != "" {
+		target = fmt.Sprintf("%s %s", e.Endpoint, e.Name)
+	}
+
+	if e.Status != 0 {
+		return fmt.Sprintf("%s: HTTP %d: %v", target, e.Status, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v", target, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// notFound returns the sentinel error for a missing nation or region.
+func notFound(endpoint string) error {
+	switch endpoint {
+	case "nation":
+		return ErrNationNotFound
+	case "region":
+		return ErrRegionNotFound
+	default:
+		return errors.New("not found")
+	}
+}
+
+var tags = regexp.MustCompile(`<[^>]*>`)
+
+// maxMessage bounds how much of an error page is quoted in an error.
+const maxMessage = 200
+
+// errorPage returns a non-nil error if body is a NationStates error page
+// rather than an API response: either an HTML page, which the site serves for
+// unknown nations and regions and when it is down, or an XML <ERROR> element.
+func errorPage(body []byte) error {
+	trimmed := bytes.TrimSpace(body)
+	lower := bytes.ToLower(trimmed)
+
+	switch {
+	case len(trimmed) == 0:
+		return errors.New("empty response")
+	case bytes.HasPrefix(lower, []byte("<!doctype html")), bytes.HasPrefix(lower, []byte("<html")):
+		return fmt.Errorf("unexpected HTML response: %s", pageMessage(trimmed))
+	case bytes.HasPrefix(lower, []byte("<error")):
+		return fmt.Errorf("API error: %s", pageMessage(trimmed))
+	}
+
+	return nil
+}
+
+// pageMessage reduces an error page to a short line of text.
+func pageMessage(body []byte) string {
+	if start := bytes.Index(bytes.ToLower(body), []byte("<body")); start >= 0 {
+		body = body[start:]
+	}
+
+	message := strings.Join(strings.Fields(tags.ReplaceAllString(string(body), " ")), " ")
+	if len(message) > maxMessage {
+		message = message[:maxMessage] + "..."
+	}
+	if message == "" {
+		message = "no message"
+	}
+
+	return message
+}
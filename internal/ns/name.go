@@ -0,0 +1,57 @@
+package ns
+
+import (
+	"sort"
+	"strings"
+)
+
+// NationName is a nation's name in the canonical form the API uses: lowercase,
+// with underscores in place of spaces. "Le Libertia", "le libertia" and
+// "le_libertia" are all the nation le_libertia. Names are canonicalized once,
+// where they enter the program, so they can be compared directly afterwards.
+type NationName string
+
+// Canonical returns the canonical form of a nation or region name as it might
+// be typed, listed on a sheet, or shown in a dump.
+func Canonical(name string) NationName {
+	return NationName(strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "_")))
+}
+
+// UnmarshalText canonicalizes names read from flags, config files and XML.
+func (n *NationName) UnmarshalText(text []byte) error {
+	*n = Canonical(string(text))
+	return nil
+}
+
+func (n NationName) String() string {
+	return string(n)
+}
+
+// ParseNames splits a sep-separated list of names, as found in endorsement
+// and member shards, dropping the empty entries NationStates returns for an
+// empty list.
+func ParseNames(list string, sep string) []NationName {
+	names := []NationName{}
+	for _, name := range strings.Split(list, sep) {
+		if name := Canonical(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// JoinNames joins names with sep, for building links and lists.
+func JoinNames(names []NationName, sep string) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = string(name)
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// SortNames sorts names alphabetically.
+func SortNames(names []NationName) {
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+}
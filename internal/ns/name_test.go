@@ -0,0 +1,49 @@
+package ns
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		name string
+		want NationName
+	}{
+		{"upc", "upc"},
+		{"Upc", "upc"},
+		{"Le Libertia", "le_libertia"},
+		{"le_libertia", "le_libertia"},
+		{"  Mancheseva City\r", "mancheseva_city"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := Canonical(test.name); got != test.want {
+			t.Errorf("Canonical(%q) = %q, want %q", test.name, got, test.want)
+		}
+
+		var got NationName
+		if err := got.UnmarshalText([]byte(test.name)); err != nil || got != test.want {
+			t.Errorf("UnmarshalText(%q) = %q, %v, want %q", test.name, got, err, test.want)
+		}
+	}
+}
+
+func TestParseNames(t *testing.T) {
+	tests := []struct {
+		list string
+		sep  string
+		want []NationName
+	}{
+		{"upc,le_libertia", ",", []NationName{"upc", "le_libertia"}},
+		{"Upc:Le Libertia:", ":", []NationName{"upc", "le_libertia"}},
+		{"", ",", []NationName{}},
+	}
+
+	for _, test := range tests {
+		if got := ParseNames(test.list, test.sep); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseNames(%q) = %q, want %q", test.list, got, test.want)
+		}
+	}
+}
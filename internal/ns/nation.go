@@ -0,0 +1,20 @@
+package ns
+
+import "strings"
+
+type Nation struct {
+	ID           string `xml:"id,attr"`
+	Endorsements string `xml:"ENDORSEMENTS"`
+	Region       string `xml:"REGION"`
+}
+
+// Nation fetches the requested shards of a nation.
+func (c *Client) Nation(name string, shards ...string) (Nation, error) {
+	var nation Nation
+	err := c.api("nation", name, strings.Join(shards, "+"), &nation)
+	if err == nil && nation.ID == "" {
+		err = &Error{Endpoint: "nation", Name: name, Err: ErrNationNotFound}
+	}
+
+	return nation, err
+}
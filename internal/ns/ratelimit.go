@@ -0,0 +1,134 @@
+package ns
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// reserve is the number of requests left unspent in each rate limit window,
+// so that a user running a tool can still browse the site or run another
+// script without being locked out.
+const reserve = 2
+
+// window is how long to back off after a 429 response that did not say how
+// long to wait; NationStates rate limits are counted over 30 seconds.
+const window = 30 * time.Second
+
+// Limiter paces API requests using the RateLimit-Remaining, RateLimit-Reset
+// and Retry-After headers NationStates sends with every response. Until a
+// response has been seen it lets requests through immediately. The zero value
+// is ready to use, reading the real clock.
+type Limiter struct {
+	mu        sync.Mutex
+	remaining int
+	reset     time.Time
+	known     bool
+
+	// now and sleep stand in for time.Now and time.Sleep when set, so that
+	// tests can use a fake clock.
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewLimiter returns a limiter for a new client, reading the real clock. It is
+// the same as a zero Limiter.
+func NewLimiter() *Limiter {
+	return &Limiter{}
+}
+
+func (l *Limiter) clock() time.Time {
+	if l.now == nil {
+		return time.Now()
+	}
+
+	return l.now()
+}
+
+func (l *Limiter) pause(d time.Duration) {
+	if l.sleep == nil {
+		time.Sleep(d)
+		return
+	}
+
+	l.sleep(d)
+}
+
+// Wait blocks until a request can be made without exceeding the rate limit.
+func (l *Limiter) Wait() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.known {
+		return
+	}
+
+	now := l.clock()
+	if !now.Before(l.reset) {
+		l.known = false
+		return
+	}
+
+	if l.remaining > reserve {
+		l.remaining--
+		return
+	}
+
+	l.pause(l.reset.Sub(now))
+	l.known = false
+}
+
+// Update records the rate limit state reported by a response.
+func (l *Limiter) Update(response *http.Response) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock()
+	header := response.Header
+
+	if after, ok := headerSeconds(header, "Retry-After"); ok {
+		l.remaining = 0
+		l.reset = now.Add(after)
+		l.known = true
+		return
+	}
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		l.remaining = 0
+		l.reset = now.Add(window)
+		l.known = true
+		return
+	}
+
+	remaining, err := strconv.Atoi(header.Get("RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	reset, ok := headerSeconds(header, "RateLimit-Reset")
+	if !ok {
+		return
+	}
+
+	l.remaining = remaining
+	l.reset = now.Add(reset)
+	l.known = true
+}
+
+func headerSeconds(header http.Header, key string) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(header.Get(key))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
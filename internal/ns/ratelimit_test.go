@@ -0,0 +1,128 @@
+package ns
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when slept on or advanced.
+type fakeClock struct {
+	now   time.Time
+	slept []time.Duration
+}
+
+func (c *fakeClock) limiter() *Limiter {
+	return &Limiter{
+		now: func() time.Time { return c.now },
+		sleep: func(d time.Duration) {
+			c.slept = append(c.slept, d)
+			c.now = c.now.Add(d)
+		},
+	}
+}
+
+func response(status int, headers ...string) *http.Response {
+	r := &http.Response{StatusCode: status, Header: make(http.Header)}
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+
+	return r
+}
+
+func TestLimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []*http.Response
+		advance   time.Duration
+		waits     int
+		want      []time.Duration
+	}{
+		{
+			name:  "no response seen",
+			waits: 3,
+		},
+		{
+			name:      "spends down to the reserve",
+			responses: []*http.Response{response(200, "RateLimit-Remaining", "5", "RateLimit-Reset", "10")},
+			waits:     3,
+		},
+		{
+			name:      "waits for the reset at the reserve",
+			responses: []*http.Response{response(200, "RateLimit-Remaining", "5", "RateLimit-Reset", "10")},
+			waits:     5,
+			want:      []time.Duration{10 * time.Second},
+		},
+		{
+			name:      "retry after",
+			responses: []*http.Response{response(200, "RateLimit-Remaining", "40", "RateLimit-Reset", "10", "Retry-After", "7")},
+			waits:     2,
+			want:      []time.Duration{7 * time.Second},
+		},
+		{
+			name:      "429 with retry after",
+			responses: []*http.Response{response(429, "Retry-After", "5")},
+			waits:     1,
+			want:      []time.Duration{5 * time.Second},
+		},
+		{
+			name:      "429 without a header",
+			responses: []*http.Response{response(429)},
+			waits:     1,
+			want:      []time.Duration{window},
+		},
+		{
+			name:      "reset has passed",
+			responses: []*http.Response{response(200, "RateLimit-Remaining", "0", "RateLimit-Reset", "10")},
+			advance:   11 * time.Second,
+			waits:     2,
+		},
+		{
+			name:      "reset only partly passed",
+			responses: []*http.Response{response(200, "RateLimit-Remaining", "0", "RateLimit-Reset", "10")},
+			advance:   4 * time.Second,
+			waits:     1,
+			want:      []time.Duration{6 * time.Second},
+		},
+		{
+			name:      "latest response wins",
+			responses: []*http.Response{response(200, "RateLimit-Remaining", "0", "RateLimit-Reset", "10"), response(200, "RateLimit-Remaining", "40", "RateLimit-Reset", "10")},
+			waits:     3,
+		},
+		{
+			name:      "unreadable headers are ignored",
+			responses: []*http.Response{response(200, "RateLimit-Remaining", "many", "RateLimit-Reset", "10"), response(200, "RateLimit-Remaining", "0", "RateLimit-Reset", "-1")},
+			waits:     2,
+		},
+	}
+
+	for _, test := range tests {
+		clock := &fakeClock{now: time.Unix(0, 0)}
+		l := clock.limiter()
+
+		for _, r := range test.responses {
+			l.Update(r)
+		}
+		clock.now = clock.now.Add(test.advance)
+
+		for i := 0; i < test.waits; i++ {
+			l.Wait()
+		}
+
+		if !reflect.DeepEqual(clock.slept, test.want) {
+			t.Errorf("%s: slept %v, want %v", test.name, clock.slept, test.want)
+		}
+	}
+}
+
+func TestLimiterZeroValue(t *testing.T) {
+	var l Limiter
+	l.Update(response(200, "RateLimit-Remaining", "40", "RateLimit-Reset", "30"))
+	l.Wait()
+
+	var none *Limiter
+	none.Update(response(429))
+	none.Wait()
+}
@@ -0,0 +1,111 @@
+package ns
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Region struct {
+	ID          string      `xml:"id,attr"`
+	Delegate    NationName  `xml:"DELEGATE"`
+	WANations   string      `xml:"UNNATIONS"`
+	Officers    []Officer   `xml:"OFFICERS>OFFICER"`
+	CensusRanks CensusRanks `xml:"CENSUSRANKS"`
+}
+
+type Officer struct {
+	Nation    NationName `xml:"NATION"`
+	Office    string     `xml:"OFFICE"`
+	Authority string     `xml:"AUTHORITY"`
+}
+
+type CensusRanks struct {
+	ID      string         `xml:"id,attr"`
+	Nations []CensusNation `xml:"NATIONS>NATION"`
+}
+
+type CensusNation struct {
+	Name  NationName `xml:"NAME"`
+	Rank  int        `xml:"RANK"`
+	Score int        `xml:"SCORE"`
+}
+
+// EndorsementsScale is the census scale ranking nations by endorsements
+// received.
+const EndorsementsScale = 66
+
+// censusPageSize is the number of nations NationStates returns per
+// censusranks page.
+const censusPageSize = 20
+
+// Region fetches the requested shards of a region.
+func (c *Client) Region(name string, shards ...string) (Region, error) {
+	var region Region
+	err := c.api("region", name, strings.Join(shards, "+"), &region)
+	if err == nil && region.ID == "" {
+		err = &Error{Endpoint: "region", Name: name, Err: ErrRegionNotFound}
+	}
+
+	return region, err
+}
+
+// CensusRanks pages through a region's ranking on the given census scale and
+// returns every nation with a positive score, highest first.
+func (c *Client) CensusRanks(region string, scale int) ([]CensusNation, error) {
+	var nations []CensusNation
+
+	offset := 1
+	for {
+		fmt.Fprintf(os.Stderr, "Checking nations %v through %v\n", offset, offset+censusPageSize)
+
+		var page Region
+		err := c.api("region", region, fmt.Sprintf("censusranks;scale=%d;start=%d", scale, offset), &page)
+		if err != nil {
+			return nations, err
+		}
+
+		if page.ID == "" {
+			return nations, &Error{Endpoint: "region", Name: region, Err: ErrRegionNotFound}
+		}
+
+		for _, nation := range page.CensusRanks.Nations {
+			if nation.Score <= 0 {
+				return nations, nil
+			}
+			nations = append(nations, nation)
+		}
+
+		if len(page.CensusRanks.Nations) < censusPageSize {
+			return nations, nil
+		}
+
+		offset += censusPageSize
+	}
+}
+
+// WANations returns the World Assembly members of a region. A region with no
+// members gives an empty list.
+func (c *Client) WANations(region string) ([]NationName, error) {
+	reg, err := c.Region(region, "wanations")
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseNames(reg.WANations, ","), nil
+}
+
+// Officers returns the nations holding a regional office.
+func (c *Client) Officers(region string) ([]NationName, error) {
+	reg, err := c.Region(region, "officers")
+	if err != nil {
+		return nil, err
+	}
+
+	officers := make([]NationName, 0, len(reg.Officers))
+	for _, officer := range reg.Officers {
+		officers = append(officers, officer.Nation)
+	}
+
+	return officers, nil
+}
@@ -0,0 +1,108 @@
+package ns
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fixtureServer serves the named testdata file for every API request.
+func fixtureServer(t *testing.T, fixture string) *Client {
+	t.Helper()
+
+	return fixtureServerStatus(t, fixture, http.StatusOK)
+}
+
+// fixtureServerStatus serves the named testdata file with the given status
+// for every API request.
+func fixtureServerStatus(t *testing.T, fixture string, status int) *Client {
+	t.Helper()
+
+	body, err := os.ReadFile("testdata/" + fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return &Client{BaseURL: server.URL, HTTPClient: server.Client()}
+}
+
+func TestWANations(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []NationName
+	}{
+		{"region_wanations.xml", []NationName{"le_libertia", "upc", "mancheseva_city", "pichtonia", "new_joiner"}},
+		{"region_wanations_empty.xml", []NationName{}},
+	}
+
+	for _, test := range tests {
+		client := fixtureServer(t, test.fixture)
+
+		got, err := client.WANations("europeia")
+		if err != nil {
+			t.Fatalf("%s: %v", test.fixture, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: WANations() = %q, want %q", test.fixture, got, test.want)
+		}
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	tests := []struct {
+		fixture string
+		status  int
+		wantErr error
+		want    string
+	}{
+		{"error_page.html", http.StatusOK, nil, "unexpected HTML response: Not Found There is no region by that name."},
+		{"error_page.html", http.StatusNotFound, ErrRegionNotFound, "region does not exist"},
+		{"error_api.xml", http.StatusOK, nil, "API error: Unknown request shard: wanation"},
+		{"empty.xml", http.StatusOK, nil, "empty response"},
+		{"error_conflict.html", http.StatusConflict, nil, "Conflict: the server is busy updating. Please try again later."},
+		{"empty.xml", http.StatusInternalServerError, nil, "no message"},
+	}
+
+	for _, test := range tests {
+		client := fixtureServerStatus(t, test.fixture, test.status)
+
+		_, err := client.WANations("europeia")
+
+		var apiErr *Error
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("%s/%d: WANations() error = %v, want an *Error", test.fixture, test.status, err)
+		}
+
+		if apiErr.Status != test.status {
+			t.Errorf("%s/%d: status = %d, want %d", test.fixture, test.status, apiErr.Status, test.status)
+		}
+
+		if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+			t.Errorf("%s/%d: error = %v, want %v", test.fixture, test.status, err, test.wantErr)
+		}
+
+		if got := apiErr.Err.Error(); got != test.want {
+			t.Errorf("%s/%d: message = %q, want %q", test.fixture, test.status, got, test.want)
+		}
+	}
+}
+
+func TestPageMessageIsShort(t *testing.T) {
+	long := "<html><body><p>" + strings.Repeat("word ", 100) + "</p></body></html>"
+
+	got := pageMessage([]byte(long))
+	if len(got) != maxMessage+len("...") || !strings.HasSuffix(got, "...") {
+		t.Errorf("pageMessage() = %q (%d bytes), want %d bytes ending in ...", got, len(got), maxMessage+3)
+	}
+}
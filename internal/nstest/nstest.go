@@ -0,0 +1,366 @@
+// Package nstest runs a fake NationStates server for tests. It serves a single
+// region in the XML the live API and daily dumps use, so the tools can be
+// tested without network access.
+package nstest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/source"
+)
+
+// censusPageSize is the number of nations the live API returns per
+// censusranks page.
+const censusPageSize = 20
+
+// Nation is a nation in the fake region. Endorsements lists the nations
+// endorsing it.
+type Nation struct {
+	Name         string
+	WA           bool
+	Endorsements []string
+}
+
+// Region is the region served by the fake server. Names are in the
+// lowercase, underscored form the API uses; the dumps use display names.
+type Region struct {
+	Name     string
+	Delegate string
+	Officers []string
+	Nations  []Nation
+}
+
+// Server is a fake NationStates server for one region.
+type Server struct {
+	Region Region
+
+	// DumpModified is when the daily dumps were generated. It is sent as
+	// Last-Modified, and a request with an If-Modified-Since no earlier gets
+	// 304 Not Modified. If zero, no Last-Modified is sent and every request
+	// gets the dump.
+	DumpModified time.Time
+
+	t      testing.TB
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []string
+}
+
+// NewServer starts a fake server for region. It is closed when the test ends.
+func NewServer(t testing.TB, region Region) *Server {
+	t.Helper()
+
+	s := &Server{Region: region, t: t}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+// Client returns an API client for the fake server, without rate limiting.
+func (s *Server) Client() *ns.Client {
+	return &ns.Client{
+		BaseURL:    s.server.URL,
+		HTTPClient: s.server.Client(),
+		UserAgent:  "nstest",
+	}
+}
+
+// APISource returns a source reading the region from the fake API.
+func (s *Server) APISource() source.Source {
+	return source.API{Client: s.Client(), Region: s.Region.Name}
+}
+
+// DumpSource returns a source reading the region from the fake daily dumps,
+// downloaded into a temporary cache.
+func (s *Server) DumpSource() source.Source {
+	s.t.Helper()
+
+	src, err := source.Open(source.Options{Kind: source.KindDump, CacheDir: s.t.TempDir()}, s.Client(), s.Region.Name)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	return src
+}
+
+// Sources returns the region's API and dump sources by kind, for tests that
+// should behave the same with either.
+func (s *Server) Sources() map[string]source.Source {
+	return map[string]source.Source{
+		source.KindAPI:  s.APISource(),
+		source.KindDump: s.DumpSource(),
+	}
+}
+
+// Requests returns the path and query of every request served so far.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+// Names returns n nation names made from prefix, for filling endorsement
+// lists.
+func Names(prefix string, n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s_%d", prefix, i+1)
+	}
+
+	return names
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+	s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/cgi-bin/api.cgi":
+		s.serveAPI(w, r)
+	case "/pages/nations.xml.gz":
+		s.serveDump(w, r, s.nationsDump())
+	case "/pages/regions.xml.gz":
+		s.serveDump(w, r, s.regionsDump())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveAPI answers nation and region requests. The query is split by hand
+// because censusranks shards contain semicolons, which net/url rejects.
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request) {
+	query := map[string]string{}
+	for _, pair := range strings.Split(r.URL.RawQuery, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+		query[key] = value
+	}
+
+	shards := strings.Split(query["q"], "+")
+
+	if name, ok := query["nation"]; ok {
+		nation, ok := s.nation(name)
+		if !ok {
+			http.Error(w, "Unknown nation", http.StatusNotFound)
+			return
+		}
+		s.writeNation(w, nation, shards)
+		return
+	}
+
+	if name, ok := query["region"]; ok {
+		if name != s.Region.Name {
+			http.Error(w, "Unknown region", http.StatusNotFound)
+			return
+		}
+		s.writeRegion(w, shards)
+		return
+	}
+
+	http.Error(w, "Bad request", http.StatusBadRequest)
+}
+
+func (s *Server) nation(name string) (Nation, bool) {
+	for _, nation := range s.Region.Nations {
+		if nation.Name == name {
+			return nation, true
+		}
+	}
+
+	return Nation{}, false
+}
+
+func (s *Server) writeNation(w http.ResponseWriter, nation Nation, shards []string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<NATION id=%q>\n", nation.Name)
+	for _, shard := range shards {
+		switch shard {
+		case "endorsements":
+			fmt.Fprintf(&b, "<ENDORSEMENTS>%s</ENDORSEMENTS>\n", strings.Join(nation.Endorsements, ","))
+		case "region":
+			fmt.Fprintf(&b, "<REGION>%s</REGION>\n", display(s.Region.Name))
+		}
+	}
+	b.WriteString("</NATION>\n")
+
+	w.Write([]byte(b.String()))
+}
+
+func (s *Server) writeRegion(w http.ResponseWriter, shards []string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<REGION id=%q>\n", s.Region.Name)
+	for _, shard := range shards {
+		name, params, _ := strings.Cut(shard, ";")
+		switch name {
+		case "delegate":
+			fmt.Fprintf(&b, "<DELEGATE>%s</DELEGATE>\n", s.delegate())
+		case "wanations":
+			fmt.Fprintf(&b, "<UNNATIONS>%s</UNNATIONS>\n", strings.Join(s.wa(), ","))
+		case "officers":
+			b.WriteString("<OFFICERS>\n")
+			for _, officer := range s.Region.Officers {
+				fmt.Fprintf(&b, "<OFFICER><NATION>%s</NATION><OFFICE>Officer</OFFICE><AUTHORITY>X</AUTHORITY></OFFICER>\n", officer)
+			}
+			b.WriteString("</OFFICERS>\n")
+		case "censusranks":
+			s.writeCensusRanks(&b, params)
+		}
+	}
+	b.WriteString("</REGION>\n")
+
+	w.Write([]byte(b.String()))
+}
+
+// writeCensusRanks writes one page of the endorsements ranking. Every nation
+// is ranked, including those with no endorsements, as on the live site.
+func (s *Server) writeCensusRanks(b *strings.Builder, params string) {
+	scale, start := 0, 1
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(param, "=")
+		n, _ := strconv.Atoi(value)
+		switch key {
+		case "scale":
+			scale = n
+		case "start":
+			start = n
+		}
+	}
+
+	nations := append([]Nation(nil), s.Region.Nations...)
+	sort.SliceStable(nations, func(i, j int) bool {
+		if len(nations[i].Endorsements) != len(nations[j].Endorsements) {
+			return len(nations[i].Endorsements) > len(nations[j].Endorsements)
+		}
+		return nations[i].Name < nations[j].Name
+	})
+
+	fmt.Fprintf(b, "<CENSUSRANKS id=\"%d\"><NATIONS>\n", scale)
+	for i := start - 1; i >= 0 && i < len(nations) && i < start-1+censusPageSize; i++ {
+		fmt.Fprintf(b, "<NATION><NAME>%s</NAME><RANK>%d</RANK><SCORE>%d</SCORE></NATION>\n", nations[i].Name, i+1, len(nations[i].Endorsements))
+	}
+	b.WriteString("</NATIONS></CENSUSRANKS>\n")
+}
+
+func (s *Server) serveDump(w http.ResponseWriter, r *http.Request, body string) {
+	if !s.DumpModified.IsZero() {
+		since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		if err == nil && !s.DumpModified.Truncate(time.Second).After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", s.DumpModified.UTC().Format(http.TimeFormat))
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(body))
+	gz.Close()
+
+	w.Write(buf.Bytes())
+}
+
+// nationsDump returns the region's nations as they appear in nations.xml.gz,
+// followed by a nation from another region that readers must skip.
+func (s *Server) nationsDump() string {
+	var b strings.Builder
+	b.WriteString("<NATIONS api_version=\"12\">\n")
+	for _, nation := range s.Region.Nations {
+		status := "Non-member"
+		if nation.Name == s.Region.Delegate {
+			status = "WA Delegate"
+		} else if nation.WA {
+			status = "WA Member"
+		}
+
+		fmt.Fprintf(&b, "<NATION><NAME>%s</NAME><UNSTATUS>%s</UNSTATUS><ENDORSEMENTS>%s</ENDORSEMENTS><REGION>%s</REGION></NATION>\n",
+			display(nation.Name), status, strings.Join(nation.Endorsements, ","), display(s.Region.Name))
+	}
+	b.WriteString("<NATION><NAME>Outsider</NAME><UNSTATUS>WA Member</UNSTATUS><ENDORSEMENTS></ENDORSEMENTS><REGION>Elsewhere</REGION></NATION>\n")
+	b.WriteString("</NATIONS>\n")
+
+	return b.String()
+}
+
+// regionsDump returns the region as it appears in regions.xml.gz.
+func (s *Server) regionsDump() string {
+	names := make([]string, 0, len(s.Region.Nations))
+	for _, nation := range s.Region.Nations {
+		names = append(names, display(nation.Name))
+	}
+
+	var b strings.Builder
+	b.WriteString("<REGIONS>\n<REGION>")
+	fmt.Fprintf(&b, "<NAME>%s</NAME><NATIONS>%s</NATIONS><DELEGATE>%s</DELEGATE><OFFICERS>", display(s.Region.Name), strings.Join(names, ":"), s.delegate())
+	for _, officer := range s.Region.Officers {
+		fmt.Fprintf(&b, "<OFFICER><NATION>%s</NATION><OFFICE>Officer</OFFICE><AUTHORITY>X</AUTHORITY></OFFICER>", officer)
+	}
+	b.WriteString("</OFFICERS></REGION>\n</REGIONS>\n")
+
+	return b.String()
+}
+
+// delegate returns the delegate as the API reports it: "0" for none.
+func (s *Server) delegate() string {
+	if s.Region.Delegate == "" {
+		return "0"
+	}
+
+	return s.Region.Delegate
+}
+
+func (s *Server) wa() []string {
+	var wa []string
+	for _, nation := range s.Region.Nations {
+		if nation.WA || nation.Name == s.Region.Delegate {
+			wa = append(wa, nation.Name)
+		}
+	}
+
+	return wa
+}
+
+// display turns an API name into the capitalised display name the dumps use.
+func display(name string) string {
+	words := strings.Split(name, "_")
+	for i, word := range words {
+		if word != "" {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	return html.EscapeString(strings.Join(words, " "))
+}
+
+// SyntheticRegion returns a region of size WA nations for benchmarks. Nation
+// i is endorsed by the i%60 nations after it, so endorsement counts range
+// from 0 to 59.
+func SyntheticRegion(name string, size int) Region {
+	region := Region{Name: name}
+
+	names := Names("nation", size)
+	for i := range names {
+		nation := Nation{Name: names[i], WA: true}
+		for j := 1; j <= i%60 && j < size; j++ {
+			nation.Endorsements = append(nation.Endorsements, names[(i+j)%size])
+		}
+		region.Nations = append(region.Nations, nation)
+	}
+	region.Delegate = names[0]
+
+	return region
+}
@@ -0,0 +1,152 @@
+// Package policy decides which endocap applies to a nation. A region's law is
+// an ordered list of tiers; a nation falls into the first tier whose
+// conditions it meets.
+package policy
+
+import (
+	"fmt"
+
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/set"
+)
+
+// Conditions a tier may require.
+const (
+	EndorsesDelegate = "endorses_delegate"
+	Citizen          = "citizen"
+	Officer          = "officer"
+	Whitelisted      = "whitelisted"
+)
+
+// Nation is what the policy knows about a nation when choosing its tier.
+type Nation struct {
+	Name             ns.NationName
+	EndorsesDelegate bool
+	Citizen          bool
+	Officer          bool
+}
+
+type Tier struct {
+	Name      string   `toml:"name"`
+	Cap       int      `toml:"cap"`
+	Require   []string `toml:"require"`
+	Whitelist set.Set  `toml:"whitelist"`
+}
+
+// Matches reports whether n meets every condition the tier requires.
+func (t Tier) Matches(n Nation) bool {
+	for _, condition := range t.Require {
+		var ok bool
+
+		switch condition {
+		case EndorsesDelegate:
+			ok = n.EndorsesDelegate
+		case Citizen:
+			ok = n.Citizen
+		case Officer:
+			ok = n.Officer
+		case Whitelisted:
+			ok = t.Whitelist.Has(n.Name)
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+type Policy struct {
+	Tiers []Tier
+}
+
+// Standard returns the three-tier policy of base, standard and citizen caps:
+// citizens endorsing the delegate get the citizen cap, other nations endorsing
+// the delegate get the standard cap and everyone else gets the base cap.
+func Standard(base int, standard int, citizen int) Policy {
+	return Policy{Tiers: []Tier{
+		{Name: "citizen", Cap: citizen, Require: []string{EndorsesDelegate, Citizen}},
+		{Name: "standard", Cap: standard, Require: []string{EndorsesDelegate}},
+		{Name: "base", Cap: base},
+	}}
+}
+
+// TierFor returns the first tier n falls into.
+func (p Policy) TierFor(n Nation) Tier {
+	for _, tier := range p.Tiers {
+		if tier.Matches(n) {
+			return tier
+		}
+	}
+
+	return p.Tiers[len(p.Tiers)-1]
+}
+
+// CapFor returns the endocap that applies to n.
+func (p Policy) CapFor(n Nation) int {
+	return p.TierFor(n).Cap
+}
+
+// Uses reports whether any tier requires the given condition, so callers can
+// skip fetching data the policy does not need.
+func (p Policy) Uses(condition string) bool {
+	for _, tier := range p.Tiers {
+		for _, required := range tier.Require {
+			if required == condition {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Validate checks that every condition is known and that the last tier has no
+// conditions, so that every nation has a cap.
+func (p Policy) Validate() error {
+	if len(p.Tiers) == 0 {
+		return fmt.Errorf("policy has no tiers")
+	}
+
+	for _, tier := range p.Tiers {
+		for _, condition := range tier.Require {
+			switch condition {
+			case EndorsesDelegate, Citizen, Officer, Whitelisted:
+			default:
+				return fmt.Errorf("tier %q: unknown condition %q", tier.Name, condition)
+			}
+		}
+	}
+
+	if last := p.Tiers[len(p.Tiers)-1]; len(last.Require) > 0 {
+		return fmt.Errorf("last tier %q must have no conditions so that every nation has a cap", last.Name)
+	}
+
+	return nil
+}
+
+// Roster holds the sets of nations used to fill in a Nation.
+type Roster struct {
+	Citizens          set.Set
+	DelegateEndorsers set.Set
+	Officers          set.Set
+}
+
+// NewRoster builds a Roster from lists of nations.
+func NewRoster(citizens []ns.NationName, delegateEndorsers []ns.NationName, officers []ns.NationName) Roster {
+	return Roster{
+		Citizens:          set.New(citizens...),
+		DelegateEndorsers: set.New(delegateEndorsers...),
+		Officers:          set.New(officers...),
+	}
+}
+
+func (r Roster) Nation(name ns.NationName) Nation {
+	return Nation{
+		Name:             name,
+		EndorsesDelegate: r.DelegateEndorsers.Has(name),
+		Citizen:          r.Citizens.Has(name),
+		Officer:          r.Officers.Has(name),
+	}
+}
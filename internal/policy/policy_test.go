@@ -0,0 +1,120 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/set"
+)
+
+// fourTiers is a law with an officer tier and a whitelist above the citizen
+// and base caps.
+var fourTiers = Policy{Tiers: []Tier{
+	{Name: "officer", Cap: 100, Require: []string{Officer}},
+	{Name: "honoured", Cap: 75, Require: []string{Whitelisted}, Whitelist: set.New("pichtonia")},
+	{Name: "citizen", Cap: 50, Require: []string{EndorsesDelegate, Citizen}},
+	{Name: "base", Cap: 10},
+}}
+
+func TestTierFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		nation Nation
+		want   string
+	}{
+		{"officer first", fourTiers, Nation{Name: "a", Officer: true, Citizen: true, EndorsesDelegate: true}, "officer"},
+		{"whitelisted", fourTiers, Nation{Name: "pichtonia"}, "honoured"},
+		{"citizen endorsing the delegate", fourTiers, Nation{Name: "a", Citizen: true, EndorsesDelegate: true}, "citizen"},
+		{"citizen not endorsing the delegate", fourTiers, Nation{Name: "a", Citizen: true}, "base"},
+		{"nobody", fourTiers, Nation{Name: "a"}, "base"},
+		{"standard endorsing the delegate", Standard(10, 25, 50), Nation{Name: "a", EndorsesDelegate: true}, "standard"},
+		{"standard citizen", Standard(10, 25, 50), Nation{Name: "a", EndorsesDelegate: true, Citizen: true}, "citizen"},
+		{"standard base", Standard(10, 25, 50), Nation{Name: "a", Citizen: true}, "base"},
+	}
+
+	for _, test := range tests {
+		if got := test.policy.TierFor(test.nation).Name; got != test.want {
+			t.Errorf("%s: TierFor() = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr bool
+	}{
+		{"four tiers", fourTiers, false},
+		{"standard", Standard(10, 25, 50), false},
+		{"no tiers", Policy{}, true},
+		{"unknown condition", Policy{Tiers: []Tier{{Name: "a", Require: []string{"resident"}}, {Name: "b"}}}, true},
+		{"last tier has conditions", Policy{Tiers: []Tier{{Name: "a"}, {Name: "b", Require: []string{Citizen}}}}, true},
+	}
+
+	for _, test := range tests {
+		err := test.policy.Validate()
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: Validate() error = %v, want error %v", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestUses(t *testing.T) {
+	if !fourTiers.Uses(Officer) {
+		t.Error("Uses(officer) = false for a policy with an officer tier")
+	}
+
+	if Standard(10, 25, 50).Uses(Officer) {
+		t.Error("Uses(officer) = true for the standard policy")
+	}
+}
+
+func TestDecodeTiers(t *testing.T) {
+	const data = `
+[[tier]]
+name = "officer"
+cap = 100
+require = ["officer"]
+
+[[tier]]
+name = "honoured"
+cap = 75
+require = ["whitelisted"]
+whitelist = ["Pichtonia", "Le Libertia"]
+
+[[tier]]
+name = "citizen"
+cap = 50
+require = ["endorses_delegate", "citizen"]
+
+[[tier]]
+name = "base"
+cap = 10
+`
+
+	var cfg struct {
+		Tiers []Tier `toml:"tier"`
+	}
+	if _, err := toml.Decode(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	p := Policy{Tiers: cfg.Tiers}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Tiers) != 4 {
+		t.Fatalf("decoded %d tiers, want 4", len(p.Tiers))
+	}
+
+	for _, name := range []ns.NationName{"pichtonia", "le_libertia"} {
+		if got := p.TierFor(Nation{Name: name}); got.Name != "honoured" || got.Cap != 75 {
+			t.Errorf("TierFor(%s) = %s (%d), want honoured (75)", name, got.Name, got.Cap)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/set"
+)
+
+// ReadNations reads a list of nations from the file at path, or from standard
+// input if path is "-". The list may be a JSON or CSV report written by one of
+// the tools, in which case the "nation" column is read, or plain text with one
+// nation per line. Text reports ("name: ...") are read up to the colon, and
+// lines starting with "#" are skipped. Only the first table of a text or CSV
+// report is read, since later ones, such as a summary, do not list nations;
+// plain lists are read whole. An empty list gives an empty, not nil, slice.
+func ReadNations(path string) ([]ns.NationName, error) {
+	var data []byte
+	var err error
+	if path == Stdout {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading nations: %w", err)
+	}
+
+	nations, err := parseNations(data)
+	if err != nil {
+		return nil, fmt.Errorf("reading nations from %s: %w", describe(path), err)
+	}
+
+	return nations, nil
+}
+
+func parseNations(data []byte) ([]ns.NationName, error) {
+	trimmed := bytes.TrimSpace(data)
+
+	switch {
+	case len(trimmed) == 0:
+		return []ns.NationName{}, nil
+	case trimmed[0] == '{' || trimmed[0] == '[':
+		return parseJSON(trimmed)
+	case isCSV(trimmed):
+		return parseCSV(firstTable(trimmed))
+	case isTextReport(trimmed):
+		return parseText(firstTable(trimmed)), nil
+	default:
+		return parseText(trimmed), nil
+	}
+}
+
+// parseJSON reads a report written by writeJSON, or a plain array of names.
+func parseJSON(data []byte) ([]ns.NationName, error) {
+	if data[0] == '[' {
+		var names []ns.NationName
+		err := json.Unmarshal(data, &names)
+		return dedupe(names), err
+	}
+
+	var tables map[string][]map[string]interface{}
+	err := json.Unmarshal(data, &tables)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(tables))
+	for name := range tables {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+
+	var names []ns.NationName
+	for _, name := range keys {
+		for _, row := range tables[name] {
+			if nation, ok := row["nation"].(string); ok {
+				names = append(names, ns.Canonical(nation))
+			}
+		}
+	}
+
+	return dedupe(names), nil
+}
+
+// isCSV reports whether data starts with a CSV header naming a nation column.
+func isCSV(data []byte) bool {
+	header, _, _ := strings.Cut(string(data), "\n")
+	for _, column := range strings.Split(header, ",") {
+		if strings.TrimSpace(column) == "nation" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTextReport reports whether data starts with a text report row, which
+// unlike a nation name holds a colon, or with the "#" note of an empty table.
+func isTextReport(data []byte) bool {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+	return bytes.Contains(line, []byte(":")) || bytes.HasPrefix(line, []byte("#"))
+}
+
+// firstTable returns data up to the blank line that separates tables in text
+// and CSV reports.
+func firstTable(data []byte) []byte {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	table, _, _ := bytes.Cut(data, []byte("\n\n"))
+	return table
+}
+
+func parseCSV(data []byte) ([]ns.NationName, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	column := 0
+	for i, name := range records[0] {
+		if strings.TrimSpace(name) == "nation" {
+			column = i
+		}
+	}
+
+	var names []ns.NationName
+	for _, record := range records[1:] {
+		if column < len(record) {
+			names = append(names, ns.Canonical(record[column]))
+		}
+	}
+
+	return dedupe(names), nil
+}
+
+func parseText(data []byte) []ns.NationName {
+	var names []ns.NationName
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		name, _, _ := strings.Cut(line, ":")
+		names = append(names, ns.Canonical(name))
+	}
+
+	return dedupe(names)
+}
+
+// dedupe drops empty and repeated names, keeping the first of each.
+func dedupe(names []ns.NationName) []ns.NationName {
+	seen := set.New()
+	out := make([]ns.NationName, 0, len(names))
+	for _, name := range names {
+		if name != "" && !seen.Has(name) {
+			seen.Add(name)
+			out = append(out, name)
+		}
+	}
+
+	return out
+}
+
+func describe(path string) string {
+	if path == Stdout {
+		return "standard input"
+	}
+
+	return path
+}
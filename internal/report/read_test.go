@@ -0,0 +1,68 @@
+package report
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"rsc-tools/internal/ns"
+)
+
+func TestParseNations(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []ns.NationName
+	}{
+		{"plain list", "Upc\nle_libertia\n\nupc\n", []ns.NationName{"upc", "le_libertia"}},
+		{"text report", "a: 2\nLe Libertia: 1\n", []ns.NationName{"a", "le_libertia"}},
+		{"csv report", "nation,over\na,2\n\"Le Libertia\",1\n", []ns.NationName{"a", "le_libertia"}},
+		{"csv report with nation later", "batch,nation\n1,a\n", []ns.NationName{"a"}},
+		{"json report", `{"violators": [{"nation": "a", "over": 2}, {"nation": "Le Libertia", "over": 1}]}`, []ns.NationName{"a", "le_libertia"}},
+		{"json array", `["a", "Le Libertia"]`, []ns.NationName{"a", "le_libertia"}},
+		{"text report with summary", "a: 2\nb: 1\n\nSummary\nbase: 2 3 a\ntotal: 2 3 a\n", []ns.NationName{"a", "b"}},
+		{"csv report with summary", "nation,over\na,2\n\ntier,violators,overage,largest offender\nbase,1,2,a\n", []ns.NationName{"a"}},
+		{"json report with summary", `{"violators": [{"nation": "a", "over": 2}], "summary": [{"tier": "base", "largest_offender": "b"}]}`, []ns.NationName{"a"}},
+		{"empty text report with summary", "# No endocap violators.\n\nSummary\nbase: 0 0 \ntotal: 0 0 \n", []ns.NationName{}},
+		{"plain list with comments", "# citizens\na\nb\n", []ns.NationName{"a", "b"}},
+		{"plain list with blank lines", "a\n\nb\n", []ns.NationName{"a", "b"}},
+		{"empty", "\n", []ns.NationName{}},
+	}
+
+	for _, test := range tests {
+		got, err := parseNations([]byte(test.data))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: parseNations() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+// TestRoundTrip checks that each format a tool writes can be read back as a
+// list of nations.
+func TestRoundTrip(t *testing.T) {
+	r := Report{Tables: []Table{{
+		Name:    "Violators",
+		Columns: []string{"nation", "over"},
+		Rows:    [][]interface{}{{ns.NationName("a"), 2}, {ns.NationName("b"), 1}},
+	}}}
+
+	for _, format := range []string{Text, JSON, CSV} {
+		var b bytes.Buffer
+		if err := write(&b, r, format); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := parseNations(b.Bytes())
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		if want := []ns.NationName{"a", "b"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: read back %q, want %q", format, got, want)
+		}
+	}
+}
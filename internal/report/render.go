@@ -0,0 +1,190 @@
+package report
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"rsc-tools/internal/ns"
+)
+
+func write(w io.Writer, r Report, format string) error {
+	switch format {
+	case Text:
+		return writeText(w, r)
+	case JSON:
+		return writeJSON(w, r)
+	case CSV:
+		return writeCSV(w, r)
+	case Markdown:
+		return writeMarkdown(w, r)
+	case HTML:
+		return writeHTML(w, r)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// writeText writes one line per row: the first value, a colon, and the rest
+// separated by spaces. Tables after the first are introduced by their name.
+// An empty table's note is written as a "#" comment, which ReadNations skips.
+func writeText(w io.Writer, r Report) error {
+	b := bufio.NewWriter(w)
+
+	for i, table := range r.Tables {
+		if i > 0 {
+			fmt.Fprintf(b, "\n%s\n", table.Name)
+		}
+
+		if len(table.Rows) == 0 && table.Empty != "" {
+			fmt.Fprintf(b, "# %s\n", table.Empty)
+		}
+
+		for _, row := range table.Rows {
+			cells := make([]string, len(row))
+			for j, v := range row {
+				cells[j] = cell(v)
+			}
+
+			if len(cells) == 1 {
+				fmt.Fprintln(b, cells[0])
+			} else {
+				fmt.Fprintf(b, "%s: %s\n", cells[0], strings.Join(cells[1:], " "))
+			}
+		}
+	}
+
+	return b.Flush()
+}
+
+// writeJSON writes an object with one array per table, holding an object per
+// row keyed by column name.
+func writeJSON(w io.Writer, r Report) error {
+	out := make(map[string][]map[string]interface{}, len(r.Tables))
+	for _, table := range r.Tables {
+		rows := make([]map[string]interface{}, 0, len(table.Rows))
+		for _, row := range table.Rows {
+			object := make(map[string]interface{}, len(row))
+			for j, v := range row {
+				object[key(table.Columns[j])] = jsonValue(v)
+			}
+			rows = append(rows, object)
+		}
+		out[key(table.Name)] = rows
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case Percent:
+		return float64(v)
+	case Link:
+		return v.URL
+	case []ns.NationName:
+		if v == nil {
+			return []ns.NationName{}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// writeCSV writes each table with a header row, separating tables with a blank
+// line.
+func writeCSV(w io.Writer, r Report) error {
+	writer := csv.NewWriter(w)
+
+	for i, table := range r.Tables {
+		if i > 0 {
+			writer.Write(nil)
+		}
+
+		writer.Write(table.Columns)
+		for _, row := range table.Rows {
+			cells := make([]string, len(row))
+			for j, v := range row {
+				cells[j] = cell(v)
+			}
+			writer.Write(cells)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeMarkdown(w io.Writer, r Report) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "# %s\n", r.Title)
+
+	for _, table := range r.Tables {
+		fmt.Fprintf(b, "\n## %s\n\n", table.Name)
+		if len(table.Rows) == 0 {
+			if table.Empty != "" {
+				fmt.Fprintln(b, table.Empty)
+			} else {
+				fmt.Fprintln(b, "None.")
+			}
+			continue
+		}
+
+		fmt.Fprintf(b, "| %s |\n", strings.Join(table.Columns, " | "))
+		fmt.Fprintf(b, "|%s\n", strings.Repeat(" --- |", len(table.Columns)))
+		for _, row := range table.Rows {
+			cells := make([]string, len(row))
+			for j, v := range row {
+				if link, ok := v.(Link); ok {
+					cells[j] = fmt.Sprintf("[%s](%s)", link.Text, link.URL)
+				} else {
+					cells[j] = strings.ReplaceAll(cell(v), "|", "\\|")
+				}
+			}
+			fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+
+	return b.Flush()
+}
+
+func writeHTML(w io.Writer, r Report) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "<html><head><title>%s</title></head><body><h1>%s</h1>\n", html.EscapeString(r.Title), html.EscapeString(r.Title))
+
+	for _, table := range r.Tables {
+		fmt.Fprintf(b, "<h2>%s</h2>\n<table>\n<tr>", html.EscapeString(table.Name))
+		for _, column := range table.Columns {
+			fmt.Fprintf(b, "<th>%s</th>", html.EscapeString(column))
+		}
+		b.WriteString("</tr>\n")
+
+		if len(table.Rows) == 0 && table.Empty != "" {
+			fmt.Fprintf(b, "<tr><td colspan=\"%d\">%s</td></tr>\n", len(table.Columns), html.EscapeString(table.Empty))
+		}
+
+		for _, row := range table.Rows {
+			b.WriteString("<tr>")
+			for _, v := range row {
+				if link, ok := v.(Link); ok {
+					fmt.Fprintf(b, "<td><a href=\"%s\">%s</a></td>", html.EscapeString(link.URL), html.EscapeString(link.Text))
+				} else {
+					fmt.Fprintf(b, "<td>%s</td>", html.EscapeString(cell(v)))
+				}
+			}
+			b.WriteString("</tr>\n")
+		}
+
+		b.WriteString("</table>\n")
+	}
+
+	b.WriteString("</body></html>\n")
+	return b.Flush()
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"rsc-tools/internal/ns"
+)
+
+var sample = Report{
+	Title: "Sample",
+	Tables: []Table{{
+		Name:    "Endorsers",
+		Columns: []string{"nation", "percentage", "endorsing", "link"},
+		Rows: [][]interface{}{
+			{ns.NationName("upc"), Percent(50), []ns.NationName{"a", "b"}, Link{Text: "upc", URL: "https://www.nationstates.net/nation=upc"}},
+		},
+	}},
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{Text, "upc: 50.00% a,b https://www.nationstates.net/nation=upc\n"},
+		{CSV, "nation,percentage,endorsing,link\nupc,50.00%,\"a,b\",https://www.nationstates.net/nation=upc\n"},
+		{JSON, `{
+  "endorsers": [
+    {
+      "endorsing": [
+        "a",
+        "b"
+      ],
+      "link": "https://www.nationstates.net/nation=upc",
+      "nation": "upc",
+      "percentage": 50
+    }
+  ]
+}
+`},
+		{Markdown, "# Sample\n\n## Endorsers\n\n| nation | percentage | endorsing | link |\n| --- | --- | --- | --- |\n| upc | 50.00% | a,b | [upc](https://www.nationstates.net/nation=upc) |\n"},
+		{HTML, "<html><head><title>Sample</title></head><body><h1>Sample</h1>\n<h2>Endorsers</h2>\n<table>\n" +
+			"<tr><th>nation</th><th>percentage</th><th>endorsing</th><th>link</th></tr>\n" +
+			"<tr><td>upc</td><td>50.00%</td><td>a,b</td><td><a href=\"https://www.nationstates.net/nation=upc\">upc</a></td></tr>\n" +
+			"</table>\n</body></html>\n"},
+	}
+
+	for _, test := range tests {
+		var b bytes.Buffer
+		if err := write(&b, sample, test.format); err != nil {
+			t.Fatalf("%s: %v", test.format, err)
+		}
+
+		if got := b.String(); got != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.format, got, test.want)
+		}
+	}
+}
+
+func TestWriteEmptyTable(t *testing.T) {
+	empty := Report{
+		Title:  "Sample",
+		Tables: []Table{{Name: "Endorsers", Columns: []string{"nation", "percentage"}, Empty: "No violators."}},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{Text, "# No violators.\n"},
+		{CSV, "nation,percentage\n"},
+		{JSON, "{\n  \"endorsers\": []\n}\n"},
+		{Markdown, "# Sample\n\n## Endorsers\n\nNo violators.\n"},
+		{HTML, "<html><head><title>Sample</title></head><body><h1>Sample</h1>\n<h2>Endorsers</h2>\n<table>\n" +
+			"<tr><th>nation</th><th>percentage</th></tr>\n" +
+			"<tr><td colspan=\"2\">No violators.</td></tr>\n" +
+			"</table>\n</body></html>\n"},
+	}
+
+	for _, test := range tests {
+		var b bytes.Buffer
+		if err := write(&b, empty, test.format); err != nil {
+			t.Fatalf("%s: %v", test.format, err)
+		}
+
+		if got := b.String(); got != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.format, got, test.want)
+		}
+	}
+}
+
+func TestSaveRejectsUnknownFormat(t *testing.T) {
+	_, err := Save(sample, Options{Format: "yaml"}, Text)
+	if err == nil {
+		t.Error("Save() with format yaml succeeded, want an error")
+	}
+}
@@ -0,0 +1,122 @@
+// Package report renders the tools' results as text, JSON, CSV, Markdown or
+// HTML, so that the same results can be read by people, spreadsheets and bots.
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"rsc-tools/internal/ns"
+)
+
+// Formats a report can be written in.
+const (
+	Text     = "text"
+	JSON     = "json"
+	CSV      = "csv"
+	Markdown = "markdown"
+	HTML     = "html"
+)
+
+var extensions = map[string]string{
+	Text:     "txt",
+	JSON:     "json",
+	CSV:      "csv",
+	Markdown: "md",
+	HTML:     "html",
+}
+
+// Report is a tool's result: one or more tables under a title. Name is the
+// tool's name, used for the default output file.
+type Report struct {
+	Name   string
+	Title  string
+	Tables []Table
+}
+
+// Table is a named table of results. Each row holds one value per column.
+// Values are strings, numbers, nation names, lists of nation names, Percent or
+// Link. Empty, if set, is written by the formats meant for people when there
+// are no rows, to say why.
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]interface{}
+	Empty   string
+}
+
+// Percent is a percentage, written with two decimal places.
+type Percent float64
+
+func (p Percent) String() string {
+	return fmt.Sprintf("%.2f%%", float64(p))
+}
+
+// Link is a link to a NationStates page. Formats without links write the URL.
+type Link struct {
+	Text string
+	URL  string
+}
+
+// Stdout is the Path that writes a report to standard output.
+const Stdout = "-"
+
+// Options says how and where to write a report. An empty Format uses the
+// tool's default, and an empty Path writes <name>-report.<extension>, so that
+// each tool keeps its own file and none overwrites a script named after it.
+type Options struct {
+	Format string
+	Path   string
+}
+
+// Save writes r as opts asks, using format when opts.Format is empty, and
+// returns where it was written.
+func Save(r Report, opts Options, format string) (string, error) {
+	if opts.Format != "" {
+		format = opts.Format
+	}
+
+	extension, ok := extensions[format]
+	if !ok {
+		return "", fmt.Errorf("unknown format %q: use %s, %s, %s, %s or %s", format, Text, JSON, CSV, Markdown, HTML)
+	}
+
+	path := opts.Path
+	if path == Stdout {
+		return "standard output", write(os.Stdout, r, format)
+	}
+	if path == "" {
+		path = r.Name + "-report." + extension
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("creating %s: %w", path, err)
+	}
+	defer file.Close()
+
+	err = write(file, r, format)
+	if err != nil {
+		return "", fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	return path, file.Close()
+}
+
+// cell returns the plain-text form of a value.
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case []ns.NationName:
+		return ns.JoinNames(v, ",")
+	case Link:
+		return v.URL
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// key turns a column name into a JSON key.
+func key(column string) string {
+	return strings.ReplaceAll(strings.ToLower(column), " ", "_")
+}
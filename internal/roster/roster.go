@@ -0,0 +1,42 @@
+// Package roster reads what the endocap policy needs to know about a region's
+// nations, keeping the policy itself free of I/O.
+package roster
+
+import (
+	"fmt"
+	"os"
+
+	"rsc-tools/internal/citizens"
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/policy"
+	"rsc-tools/internal/source"
+)
+
+// Load reads the nations a policy.Roster holds: the citizens from roll, the
+// nations endorsing delegate from g, and, only if p has an officer tier, the
+// regional officers from src.
+func Load(src source.Source, g *graph.EndorsementGraph, roll citizens.Source, delegate ns.NationName, p policy.Policy) (policy.Roster, error) {
+	fmt.Fprintln(os.Stderr, "Getting citizen nations")
+	citizenNations, err := roll.Citizens()
+	if err != nil {
+		return policy.Roster{}, err
+	}
+
+	fmt.Fprintln(os.Stderr, "Getting delegate endorsements")
+	delegateEndorsements, err := g.EndorsersOf(delegate)
+	if err != nil {
+		return policy.Roster{}, fmt.Errorf("getting delegate endorsements: %w", err)
+	}
+
+	var officers []ns.NationName
+	if p.Uses(policy.Officer) {
+		fmt.Fprintln(os.Stderr, "Getting regional officers")
+		officers, err = src.Officers()
+		if err != nil {
+			return policy.Roster{}, fmt.Errorf("getting regional officers: %w", err)
+		}
+	}
+
+	return policy.NewRoster(citizenNations, delegateEndorsements, officers), nil
+}
@@ -0,0 +1,94 @@
+package roster
+
+import (
+	"strings"
+	"testing"
+
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/nstest"
+	"rsc-tools/internal/policy"
+)
+
+// officerTiers is a law with an officer tier above the standard ones.
+var officerTiers = policy.Policy{Tiers: []policy.Tier{
+	{Name: "officer", Cap: 100, Require: []string{policy.Officer}},
+	{Name: "citizen", Cap: 50, Require: []string{policy.EndorsesDelegate, policy.Citizen}},
+	{Name: "base", Cap: 10},
+}}
+
+// roll is a citizen list held in memory.
+type roll []ns.NationName
+
+func (r roll) Citizens() ([]ns.NationName, error) {
+	return r, nil
+}
+
+func TestLoad(t *testing.T) {
+	region := nstest.Region{
+		Name:     "europeia",
+		Delegate: "upc",
+		Officers: []string{"o"},
+		Nations: []nstest.Nation{
+			{Name: "upc", WA: true, Endorsements: []string{"a", "o"}},
+			{Name: "a", WA: true},
+			{Name: "c", WA: true},
+			{Name: "o", WA: true},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		policy policy.Policy
+		want   map[ns.NationName]policy.Nation
+	}{
+		{
+			name:   "standard policy skips officers",
+			policy: policy.Standard(10, 25, 50),
+			want: map[ns.NationName]policy.Nation{
+				"a": {Name: "a", EndorsesDelegate: true},
+				"c": {Name: "c", Citizen: true},
+				"o": {Name: "o", EndorsesDelegate: true},
+			},
+		},
+		{
+			name:   "officer tier reads officers",
+			policy: officerTiers,
+			want: map[ns.NationName]policy.Nation{
+				"a": {Name: "a", EndorsesDelegate: true},
+				"c": {Name: "c", Citizen: true},
+				"o": {Name: "o", EndorsesDelegate: true, Officer: true},
+			},
+		},
+	}
+
+	for kind, src := range nstest.NewServer(t, region).Sources() {
+		for _, test := range tests {
+			members, err := Load(src, graph.New(src, region.Name), roll{"c"}, "upc", test.policy)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", kind, test.name, err)
+			}
+
+			for name, want := range test.want {
+				if got := members.Nation(name); got != want {
+					t.Errorf("%s/%s: Nation(%s) = %+v, want %+v", kind, test.name, name, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestLoadOnlyAsksForOfficersWhenUsed(t *testing.T) {
+	server := nstest.NewServer(t, nstest.Region{Name: "europeia", Delegate: "upc", Nations: []nstest.Nation{{Name: "upc", WA: true}}})
+	src := server.APISource()
+
+	if _, err := Load(src, graph.New(src, "europeia"), roll{}, "upc", policy.Standard(10, 25, 50)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, request := range server.Requests() {
+		if strings.Contains(request, "officers") {
+			t.Errorf("Load() asked for officers under the standard policy: %s", request)
+		}
+	}
+}
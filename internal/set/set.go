@@ -0,0 +1,65 @@
+// Package set provides a set of nation names with constant-time membership
+// checks, for the lookups the tools make once per nation in a region.
+package set
+
+import (
+	"fmt"
+
+	"rsc-tools/internal/ns"
+)
+
+// Set is a set of nation names. The zero value is an empty set that can be
+// read but not added to.
+type Set map[ns.NationName]struct{}
+
+// New returns a set holding items.
+func New(items ...ns.NationName) Set {
+	s := make(Set, len(items))
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+
+	return s
+}
+
+// Has reports whether item is in the set.
+func (s Set) Has(item ns.NationName) bool {
+	_, ok := s[item]
+	return ok
+}
+
+// Add adds item to the set.
+func (s Set) Add(item ns.NationName) {
+	s[item] = struct{}{}
+}
+
+// UnmarshalTOML reads a set from a TOML array of names, canonicalizing each.
+func (s *Set) UnmarshalTOML(data interface{}) error {
+	items, ok := data.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected a list of names, got %T", data)
+	}
+
+	*s = make(Set, len(items))
+	for _, item := range items {
+		name, ok := item.(string)
+		if !ok {
+			return fmt.Errorf("expected a name, got %T", item)
+		}
+		s.Add(ns.Canonical(name))
+	}
+
+	return nil
+}
+
+// Filter returns the names that are in the set, in the order given.
+func (s Set) Filter(names []ns.NationName) []ns.NationName {
+	var out []ns.NationName
+	for _, name := range names {
+		if s.Has(name) {
+			out = append(out, name)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,284 @@
+// Package source provides a region's endorsement data either from the live
+// API or from the nations daily dump, so that audits can run without any API
+// calls.
+package source
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"rsc-tools/internal/dump"
+	"rsc-tools/internal/ns"
+)
+
+// Kinds of source.
+const (
+	KindAPI  = "api"
+	KindDump = "dump"
+)
+
+// Source provides who is endorsing whom in a region.
+type Source interface {
+	// Endorsements returns the nations endorsing nation.
+	Endorsements(nation ns.NationName) ([]ns.NationName, error)
+
+	// EndorsementCounts returns every nation in the region with at least one
+	// endorsement, most endorsed first.
+	EndorsementCounts() ([]ns.CensusNation, error)
+
+	// WANations returns the region's World Assembly members.
+	WANations() ([]ns.NationName, error)
+
+	// Officers returns the nations holding a regional office.
+	Officers() ([]ns.NationName, error)
+
+	// Delegate returns the region's WA delegate, or an empty string if it has
+	// none.
+	Delegate() (ns.NationName, error)
+}
+
+// Options selects a source. With Kind "dump", NationsDump and RegionsDump
+// name nations.xml.gz and regions.xml.gz files to read; the day's cached
+// dumps in CacheDir are used for any that are not set.
+type Options struct {
+	Kind        string
+	NationsDump string
+	RegionsDump string
+	CacheDir    string
+}
+
+// Open returns the source selected by opts for region.
+func Open(opts Options, client *ns.Client, region string) (Source, error) {
+	switch opts.Kind {
+	case "", KindAPI:
+		return API{Client: client, Region: region}, nil
+	case KindDump:
+		cache := dump.Cache{Dir: opts.CacheDir, Client: client}
+
+		r, err := openDump(cache, "nations", opts.NationsDump)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		fmt.Fprintln(os.Stderr, "Reading endorsements from the nations dump")
+		d, err := ReadDump(r, region)
+		if err != nil {
+			return nil, err
+		}
+
+		d.regions = func() (io.ReadCloser, error) {
+			return openDump(cache, "regions", opts.RegionsDump)
+		}
+
+		return d, nil
+	default:
+		return nil, fmt.Errorf("unknown source %q: use %s or %s", opts.Kind, KindAPI, KindDump)
+	}
+}
+
+func openDump(cache dump.Cache, name string, file string) (io.ReadCloser, error) {
+	if file != "" {
+		return os.Open(file)
+	}
+
+	return cache.Open(name)
+}
+
+// API reads endorsements from the live NationStates API.
+type API struct {
+	Client *ns.Client
+	Region string
+}
+
+func (a API) Endorsements(nation ns.NationName) ([]ns.NationName, error) {
+	n, err := a.Client.Nation(string(nation), "endorsements")
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.ParseNames(n.Endorsements, ","), nil
+}
+
+func (a API) EndorsementCounts() ([]ns.CensusNation, error) {
+	return a.Client.CensusRanks(a.Region, ns.EndorsementsScale)
+}
+
+func (a API) WANations() ([]ns.NationName, error) {
+	return a.Client.WANations(a.Region)
+}
+
+func (a API) Officers() ([]ns.NationName, error) {
+	return a.Client.Officers(a.Region)
+}
+
+func (a API) Delegate() (ns.NationName, error) {
+	region, err := a.Client.Region(a.Region, "delegate")
+	if err != nil {
+		return "", err
+	}
+
+	if region.Delegate == "0" {
+		return "", nil
+	}
+
+	return region.Delegate, nil
+}
+
+// Dump holds the endorsements and WA membership of every nation in a region,
+// read from the nations dump. The delegate and officers come from the regions
+// dump, which is only read if they are asked for.
+type Dump struct {
+	region       string
+	endorsements map[ns.NationName][]ns.NationName
+	wa           []ns.NationName
+
+	regions func() (io.ReadCloser, error)
+	info    *dump.Region
+}
+
+// ReadDump reads the nations in region from the gzipped nations dump r.
+func ReadDump(r io.Reader, region string) (*Dump, error) {
+	nations, err := dump.NewNations(r, region)
+	if err != nil {
+		return nil, err
+	}
+	defer nations.Close()
+
+	d := &Dump{region: region, endorsements: make(map[ns.NationName][]ns.NationName)}
+	for {
+		nation, err := nations.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		d.endorsements[nation.Name] = nation.EndorsementList()
+		if nation.InWA() {
+			d.wa = append(d.wa, nation.Name)
+		}
+	}
+
+	if len(d.endorsements) == 0 {
+		return nil, &ns.Error{Endpoint: "region", Name: region, Err: ns.ErrRegionNotFound}
+	}
+
+	return d, nil
+}
+
+func (d *Dump) Endorsements(nation ns.NationName) ([]ns.NationName, error) {
+	endorsements, ok := d.endorsements[nation]
+	if !ok {
+		return nil, &ns.Error{Endpoint: "nation", Name: string(nation), Err: ns.ErrNationNotFound}
+	}
+
+	return endorsements, nil
+}
+
+func (d *Dump) EndorsementCounts() ([]ns.CensusNation, error) {
+	var nations []ns.CensusNation
+	for name, endorsements := range d.endorsements {
+		if len(endorsements) > 0 {
+			nations = append(nations, ns.CensusNation{Name: name, Score: len(endorsements)})
+		}
+	}
+
+	sort.Slice(nations, func(i, j int) bool {
+		if nations[i].Score != nations[j].Score {
+			return nations[i].Score > nations[j].Score
+		}
+		return nations[i].Name < nations[j].Name
+	})
+
+	for i := range nations {
+		nations[i].Rank = i + 1
+	}
+
+	return nations, nil
+}
+
+func (d *Dump) WANations() ([]ns.NationName, error) {
+	return d.wa, nil
+}
+
+func (d *Dump) Officers() ([]ns.NationName, error) {
+	info, err := d.regionInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return info.OfficerList(), nil
+}
+
+func (d *Dump) Delegate() (ns.NationName, error) {
+	info, err := d.regionInfo()
+	if err != nil {
+		return "", err
+	}
+
+	return info.DelegateName(), nil
+}
+
+// regionInfo reads the region's entry from the regions dump the first time it
+// is needed.
+func (d *Dump) regionInfo() (dump.Region, error) {
+	if d.info != nil {
+		return *d.info, nil
+	}
+
+	if d.regions == nil {
+		return dump.Region{}, fmt.Errorf("no regions dump to read %s from", d.region)
+	}
+
+	r, err := d.regions()
+	if err != nil {
+		return dump.Region{}, err
+	}
+	defer r.Close()
+
+	fmt.Fprintln(os.Stderr, "Reading the region from the regions dump")
+	info, err := dump.FindRegion(r, d.region)
+	if err != nil {
+		return dump.Region{}, err
+	}
+
+	d.info = &info
+	return info, nil
+}
+
+// CheckDelegate returns the delegate the tools should use: given if it is
+// set, and the region's current delegate otherwise. It warns when given is
+// not the current delegate, since a stale delegate produces wrong endocaps.
+func CheckDelegate(src Source, region string, given ns.NationName) (ns.NationName, error) {
+	current, err := src.Delegate()
+	if err != nil {
+		if given != "" {
+			fmt.Fprintf(os.Stderr, "Warning: could not check the current delegate of %s: %v\n", region, err)
+			return given, nil
+		}
+		return "", fmt.Errorf("detecting the delegate: %w", err)
+	}
+
+	if given == "" {
+		if current == "" {
+			return "", fmt.Errorf("%s has no delegate; use --delegate to name one", region)
+		}
+
+		fmt.Fprintf(os.Stderr, "Detected delegate: %s\n", current)
+		return current, nil
+	}
+
+	if given != current {
+		if current == "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s is not the delegate of %s, which has no delegate\n", given, region)
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: %s is not the delegate of %s; the current delegate is %s\n", given, region, current)
+		}
+	}
+
+	return given, nil
+}
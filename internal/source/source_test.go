@@ -0,0 +1,96 @@
+package source_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/nstest"
+	"rsc-tools/internal/source"
+)
+
+var region = nstest.Region{
+	Name:     "europeia",
+	Delegate: "upc",
+	Nations: []nstest.Nation{
+		{Name: "upc", WA: true, Endorsements: []string{"a"}},
+		{Name: "a", WA: true},
+	},
+}
+
+func TestCheckDelegate(t *testing.T) {
+	tests := []struct {
+		name  string
+		given ns.NationName
+		want  ns.NationName
+	}{
+		{"detected", "", "upc"},
+		{"current", "upc", "upc"},
+		{"stale is kept", "le_libertia", "le_libertia"},
+	}
+
+	for kind, src := range nstest.NewServer(t, region).Sources() {
+		for _, test := range tests {
+			got, err := source.CheckDelegate(src, region.Name, test.given)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", kind, test.name, err)
+			}
+
+			if got != test.want {
+				t.Errorf("%s/%s: CheckDelegate() = %s, want %s", kind, test.name, got, test.want)
+			}
+		}
+	}
+}
+
+// stderr returns what f writes to standard error.
+func stderr(t *testing.T, f func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = saved }()
+
+	f()
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(out)
+}
+
+func TestCheckDelegateWarnsOfStaleDelegate(t *testing.T) {
+	tests := []struct {
+		name  string
+		given ns.NationName
+		warns bool
+	}{
+		{"current", "upc", false},
+		{"stale", "le_libertia", true},
+	}
+
+	for kind, src := range nstest.NewServer(t, region).Sources() {
+		for _, test := range tests {
+			out := stderr(t, func() {
+				if _, err := source.CheckDelegate(src, region.Name, test.given); err != nil {
+					t.Fatalf("%s/%s: %v", kind, test.name, err)
+				}
+			})
+
+			warned := strings.Contains(out, "Warning: le_libertia is not the delegate of europeia; the current delegate is upc")
+			if warned != test.warns {
+				t.Errorf("%s/%s: warned = %v, want %v; stderr:\n%s", kind, test.name, warned, test.warns, out)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package nopers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/report"
+	"rsc-tools/internal/set"
+	"rsc-tools/internal/source"
+)
+
+type Args struct {
+	User     ns.NationName
+	Region   string
+	Count    int
+	Template string
+	Source   source.Options
+	Output   report.Options
+
+	// Nations, if not nil, limits the targets to these nations.
+	Nations []ns.NationName
+}
+
+func get_endorsements(g *graph.EndorsementGraph, nation ns.NationName) ([]ns.NationName, error) {
+	endorsements, err := g.EndorsersOf(nation)
+	if err != nil {
+		return nil, fmt.Errorf("checking your endorsements: %w", err)
+	}
+
+	return endorsements, nil
+}
+
+func get_wa_nations(g *graph.EndorsementGraph) ([]ns.NationName, error) {
+	return g.WANations()
+}
+
+// get_targets returns the WA nations other than user that are missing from
+// user's endorsements, in the order given.
+func get_targets(user ns.NationName, endorsements []ns.NationName, wa_nations []ns.NationName) []ns.NationName {
+	var targets []ns.NationName
+	endorsers := set.New(endorsements...)
+
+	for _, n := range wa_nations {
+		if n != user && !endorsers.Has(n) {
+			targets = append(targets, n)
+		}
+	}
+
+	return targets
+}
+
+func build_report(targets []ns.NationName, template string, batchSize int) report.Report {
+	table := report.Table{Name: "Batches", Columns: []string{"batch", "nations", "link"}}
+
+	for i := 0; i < len(targets); i += batchSize {
+		batchName := fmt.Sprintf("Batch %d", i/batchSize+1)
+		end := i + batchSize
+		if end > len(targets) {
+			end = len(targets)
+		}
+		batch := targets[i:end]
+
+		url := fmt.Sprintf("https://www.nationstates.net/page=compose_telegram?tgto=%s", ns.JoinNames(batch, ","))
+		if template != "" {
+			url += "&message=" + template
+		}
+
+		table.Rows = append(table.Rows, []interface{}{i/batchSize + 1, batch, report.Link{Text: batchName, URL: url}})
+	}
+
+	return report.Report{Name: "nopers", Title: "Telegram Targets", Tables: []report.Table{table}}
+}
+
+// Run sorts the region's WA nations that are not endorsing args.User into
+// telegram batches.
+func Run(args Args) error {
+	if args.Count < 1 || args.Count > 8 {
+		args.Count = 8
+	}
+
+	if args.Template != "" {
+		args.Template = strings.ReplaceAll(args.Template, "%", "%25")
+	}
+
+	client := ns.NewClient("Nopers", args.User.String())
+
+	src, err := source.Open(args.Source, client, args.Region)
+	if err != nil {
+		return err
+	}
+
+	g := graph.New(src, args.Region)
+
+	fmt.Fprintln(os.Stderr, "Checking your endorsements")
+	endorsements, err := get_endorsements(g, args.User)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "Getting all WA nations")
+	wa_nations, err := get_wa_nations(g)
+	if err != nil {
+		return err
+	}
+
+	if args.Nations != nil {
+		wa_nations = set.New(args.Nations...).Filter(wa_nations)
+	}
+
+	targets := get_targets(args.User, endorsements, wa_nations)
+
+	path, err := report.Save(build_report(targets, args.Template, args.Count), args.Output, report.HTML)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Targets written to %s\n", path)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package nopers
+
+import (
+	"reflect"
+	"testing"
+
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/nstest"
+)
+
+// region has upc endorsed by a and b. c and d are WA members not endorsing
+// it, and n is not in the WA.
+var region = nstest.Region{
+	Name:     "europeia",
+	Delegate: "a",
+	Nations: []nstest.Nation{
+		{Name: "a", WA: true, Endorsements: []string{"upc", "b"}},
+		{Name: "b", WA: true, Endorsements: []string{"upc"}},
+		{Name: "c", WA: true, Endorsements: []string{"a"}},
+		{Name: "d", WA: true},
+		{Name: "n", WA: false},
+		{Name: "upc", WA: true, Endorsements: []string{"a", "b"}},
+	},
+}
+
+func TestGetTargets(t *testing.T) {
+	tests := []struct {
+		user ns.NationName
+		want []ns.NationName
+	}{
+		{"upc", []ns.NationName{"c", "d"}},
+		{"b", []ns.NationName{"a", "c", "d"}},
+		{"d", []ns.NationName{"a", "b", "c", "upc"}},
+	}
+
+	server := nstest.NewServer(t, region)
+
+	for kind, src := range server.Sources() {
+		for _, test := range tests {
+			g := graph.New(src, region.Name)
+
+			wa_nations, err := get_wa_nations(g)
+			if err != nil {
+				t.Fatalf("%s: %v", kind, err)
+			}
+
+			endorsements, err := get_endorsements(g, test.user)
+			if err != nil {
+				t.Fatalf("%s: %v", kind, err)
+			}
+
+			got := get_targets(test.user, endorsements, wa_nations)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s: get_targets(%s) = %v, want %v", kind, test.user, got, test.want)
+			}
+		}
+	}
+}
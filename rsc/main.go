@@ -0,0 +1,307 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/alexflint/go-arg"
+
+	"rsc-tools/endorsers"
+	"rsc-tools/internal/citizens"
+	"rsc-tools/internal/config"
+	"rsc-tools/internal/dump"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/report"
+	"rsc-tools/internal/source"
+	"rsc-tools/nopers"
+	"rsc-tools/tarters"
+	"rsc-tools/violators"
+)
+
+// SourceOptions selects where the tools read endorsements from.
+type SourceOptions struct {
+	Source      string `arg:"--source" help:"Where to read endorsements from: api or dump" default:"api"`
+	Dump        string `arg:"--dump" help:"nations.xml.gz to read with --source=dump [default: the day's cached dump]"`
+	RegionsDump string `arg:"--regions-dump" help:"regions.xml.gz to read with --source=dump [default: the day's cached dump]"`
+}
+
+func (o SourceOptions) options(cacheDir string) source.Options {
+	return source.Options{
+		Kind:        o.Source,
+		NationsDump: o.Dump,
+		RegionsDump: o.RegionsDump,
+		CacheDir:    cacheDir,
+	}
+}
+
+// InputOptions names a list of nations for a tool to work on.
+type InputOptions struct {
+	Input string `arg:"-i,--input" help:"File listing nations to work on, such as another tool's report, or - for standard input"`
+}
+
+// nations reads the input list, or returns nil if there is none.
+func (o InputOptions) nations() ([]ns.NationName, error) {
+	if o.Input == "" {
+		return nil, nil
+	}
+
+	return report.ReadNations(o.Input)
+}
+
+// OutputOptions selects how and where the tools write their results.
+type OutputOptions struct {
+	Format string `arg:"-f,--format" help:"Output format: text, json, csv, markdown or html [default: text for endorsers and violators, html for nopers and tarters]"`
+	Output string `arg:"-o,--output" help:"File to write results to, or - for standard output [default: <command>-report.<format extension>]"`
+}
+
+func (o OutputOptions) output() report.Options {
+	return report.Options{Format: o.Format, Path: o.Output}
+}
+
+type EndorsersCmd struct {
+	SourceOptions
+	InputOptions
+	OutputOptions
+	Verbose bool   `arg:"-v,--verbose" help:"Verbose output"`
+	Top     *int   `arg:"--top" help:"Only check the N violators furthest over their cap, or 0 for all [default: 20]"`
+	Score   string `arg:"-s,--score" help:"How to score endorsers: equal, overage, relative or count" default:"equal"`
+}
+
+type NopersCmd struct {
+	SourceOptions
+	InputOptions
+	OutputOptions
+	Count    int    `arg:"-n,--count" help:"Telegram batch size (1-8)" default:"8"`
+	Template string `arg:"-t,--template" help:"Telegram template"`
+}
+
+type TartersCmd struct {
+	SourceOptions
+	InputOptions
+	OutputOptions
+}
+
+type ViolatorsCmd struct {
+	SourceOptions
+	InputOptions
+	OutputOptions
+	Top *int `arg:"--top" help:"Only list the N violators furthest over their cap, or 0 for all [default: 20]"`
+}
+
+var arguments struct {
+	Config   string          `arg:"--config" help:"Config file [default: rsc.toml next to rsc]"`
+	User     ns.NationName   `arg:"-u,--user" help:"Your main nation [required unless set in the config file]"`
+	Region   string          `arg:"-r,--region" help:"Region [default: europeia]"`
+	Delegate ns.NationName   `arg:"-d,--delegate" help:"Delegate nation [default: the region's current delegate]"`
+	Excluded []ns.NationName `arg:"-x,--excluded,separate" help:"Excluded nations -- VD, RSC, etc. Use once per nation (-x nation1 -x nation2...)"`
+	Base     *int            `arg:"-b,--base" help:"Base endocap [default: 10]"`
+	Standard *int            `arg:"-e,--standard" help:"Standard endocap [default: 25]"`
+	Citizen  *int            `arg:"-c,--citizen" help:"Citizen endocap [default: 50]"`
+	Limit    *int            `arg:"-l,--limit" help:"Number of endorsements under cap to qualify a nation for endorsing [default: 5]"`
+
+	Key           string `arg:"-k,--key" help:"Google Sheets API key, needed to read citizens from a Google Sheet"`
+	CitizensSheet string `arg:"--citizens-sheet" help:"ID of the Google Sheet listing citizens [default: Europeia's citizen roll]"`
+	CitizensRange string `arg:"--citizens-range" help:"Sheet range holding citizen names [default: Citizens!C2:C]"`
+	CitizensFile  string `arg:"--citizens-file" help:"Text or CSV file listing one citizen per line"`
+	CitizensURL   string `arg:"--citizens-url" help:"URL of a page listing one citizen per line"`
+	CacheDir      string `arg:"--cache-dir" help:"Directory to keep daily dumps in [default: your user cache directory]"`
+
+	Endorsers *EndorsersCmd `arg:"subcommand:endorsers" help:"Report nations that are endorsing endocap violators"`
+	Nopers    *NopersCmd    `arg:"subcommand:nopers" help:"Sort WA nations that are not endorsing you into telegram batches"`
+	Tarters   *TartersCmd   `arg:"subcommand:tarters" help:"List nations to endorse or unendorse under the endocap"`
+	Violators *ViolatorsCmd `arg:"subcommand:violators" help:"Report nations that are exceeding their endocap"`
+}
+
+// settings loads the config file and overrides it with any flags given on the
+// command line.
+func settings() (config.Config, error) {
+	cfg, err := config.Load(arguments.Config)
+	if err != nil {
+		return cfg, err
+	}
+
+	if arguments.User != "" {
+		cfg.User = arguments.User
+	}
+	if arguments.Region != "" {
+		cfg.Region = arguments.Region
+	}
+	if arguments.Delegate != "" {
+		cfg.Delegate = arguments.Delegate
+	}
+	if arguments.Excluded != nil {
+		cfg.Excluded = arguments.Excluded
+	}
+	if arguments.Base != nil {
+		cfg.Base = *arguments.Base
+	}
+	if arguments.Standard != nil {
+		cfg.Standard = *arguments.Standard
+	}
+	if arguments.Citizen != nil {
+		cfg.Citizen = *arguments.Citizen
+	}
+	if len(cfg.Tiers) > 0 && (arguments.Base != nil || arguments.Standard != nil || arguments.Citizen != nil) {
+		return cfg, errors.New("-b, -e and -c cannot be used when the config file lists tiers")
+	}
+	if arguments.Limit != nil {
+		cfg.Limit = *arguments.Limit
+	}
+	if arguments.Key != "" {
+		cfg.Key = arguments.Key
+	}
+	if arguments.CitizensSheet != "" {
+		cfg.Citizens.Sheet = arguments.CitizensSheet
+	}
+	if arguments.CitizensRange != "" {
+		cfg.Citizens.Range = arguments.CitizensRange
+	}
+	if arguments.CitizensFile != "" {
+		cfg.Citizens.File = arguments.CitizensFile
+	}
+	if arguments.CitizensURL != "" {
+		cfg.Citizens.URL = arguments.CitizensURL
+	}
+	if arguments.CacheDir != "" {
+		cfg.CacheDir = arguments.CacheDir
+	}
+
+	if cfg.CacheDir == "" {
+		cfg.CacheDir, err = dump.DefaultDir()
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// citizenSource picks where to read the citizen list from.
+func citizenSource(cfg config.Config) citizens.Source {
+	switch {
+	case cfg.Citizens.File != "":
+		return citizens.File{Path: cfg.Citizens.File}
+	case cfg.Citizens.URL != "":
+		return citizens.URL{URL: cfg.Citizens.URL}
+	case cfg.Key != "":
+		return citizens.Sheet{Key: cfg.Key, SpreadsheetID: cfg.Citizens.Sheet, Range: cfg.Citizens.Range}
+	default:
+		fmt.Fprintln(os.Stderr, "No citizen list configured (--key, --citizens-file or --citizens-url); no nation will be treated as a citizen")
+		return citizens.None{}
+	}
+}
+
+func main() {
+	p := arg.MustParse(&arguments)
+
+	cfg, err := settings()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if cfg.User == "" {
+		p.Fail("--user is required")
+	}
+
+	endocaps, err := cfg.Policy()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	user := cfg.User
+	region := ns.Canonical(cfg.Region).String()
+	delegate := cfg.Delegate
+
+	var nations []ns.NationName
+	if cmd, ok := p.Subcommand().(interface {
+		nations() ([]ns.NationName, error)
+	}); ok {
+		nations, err = cmd.nations()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	switch {
+	case arguments.Endorsers != nil:
+		err = endorsers.Run(endorsers.Args{
+			User:      user,
+			Citizens:  citizenSource(cfg),
+			Delegate:  delegate,
+			Region:    region,
+			Excluded:  cfg.Excluded,
+			Policy:    endocaps,
+			Source:    arguments.Endorsers.options(cfg.CacheDir),
+			Output:    arguments.Endorsers.output(),
+			Violators: nations,
+			Top:       endorsersTop(arguments.Endorsers.Top, nations),
+			Score:     arguments.Endorsers.Score,
+			Verbose:   arguments.Endorsers.Verbose,
+		})
+	case arguments.Nopers != nil:
+		err = nopers.Run(nopers.Args{
+			User:     user,
+			Region:   region,
+			Count:    arguments.Nopers.Count,
+			Template: arguments.Nopers.Template,
+			Source:   arguments.Nopers.options(cfg.CacheDir),
+			Output:   arguments.Nopers.output(),
+			Nations:  nations,
+		})
+	case arguments.Tarters != nil:
+		err = tarters.Run(tarters.Args{
+			User:     user,
+			Citizens: citizenSource(cfg),
+			Delegate: delegate,
+			Region:   region,
+			Excluded: cfg.Excluded,
+			Policy:   endocaps,
+			Limit:    cfg.Limit,
+			Source:   arguments.Tarters.options(cfg.CacheDir),
+			Output:   arguments.Tarters.output(),
+			Nations:  nations,
+		})
+	case arguments.Violators != nil:
+		err = violators.Run(violators.Args{
+			User:     user,
+			Citizens: citizenSource(cfg),
+			Delegate: delegate,
+			Region:   region,
+			Excluded: cfg.Excluded,
+			Policy:   endocaps,
+			Source:   arguments.Violators.options(cfg.CacheDir),
+			Output:   arguments.Violators.output(),
+			Nations:  nations,
+			Top:      top(arguments.Violators.Top),
+		})
+	default:
+		p.Fail("missing subcommand: endorsers, nopers, tarters or violators")
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// top returns the --top given, or violators.DefaultTop, shared by endorsers
+// and violators so that both act on the same violators.
+func top(n *int) int {
+	if n == nil {
+		return violators.DefaultTop
+	}
+
+	return *n
+}
+
+// endorsersTop is top for endorsers, except that a list of violators given
+// with -i is checked whole unless --top is given, since it is already the
+// list that was published.
+func endorsersTop(n *int, given []ns.NationName) int {
+	if n == nil && given != nil {
+		return 0
+	}
+
+	return top(n)
+}
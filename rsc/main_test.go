@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"rsc-tools/internal/config"
+	"rsc-tools/internal/ns"
+	"rsc-tools/violators"
+)
+
+func TestEndorsersTop(t *testing.T) {
+	five := 5
+	zero := 0
+
+	tests := []struct {
+		name  string
+		top   *int
+		given []ns.NationName
+		want  int
+	}{
+		{"live, default", nil, nil, violators.DefaultTop},
+		{"live, given top", &five, nil, 5},
+		{"live, all", &zero, nil, 0},
+		{"list, default keeps it whole", nil, []ns.NationName{"a"}, 0},
+		{"empty list, default keeps it whole", nil, []ns.NationName{}, 0},
+		{"list, given top", &five, []ns.NationName{"a"}, 5},
+	}
+
+	for _, test := range tests {
+		if got := endorsersTop(test.top, test.given); got != test.want {
+			t.Errorf("%s: endorsersTop() = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestSettings(t *testing.T) {
+	file := `region = "europeia"
+delegate = "upc"
+excluded = ["pichtonia"]
+base = 5
+limit = 2
+cache_dir = "dumps"
+
+[citizens]
+file = "citizens.txt"
+`
+	tiers := `cache_dir = "dumps"
+
+[[tier]]
+name = "base"
+cap = 10
+`
+
+	one := 1
+	eight := 8
+
+	tests := []struct {
+		name    string
+		file    string
+		set     func()
+		want    func(*config.Config)
+		wantErr string
+	}{
+		{
+			name: "file alone",
+			file: file,
+			set:  func() {},
+			want: func(*config.Config) {},
+		},
+		{
+			name: "flags override the file",
+			file: file,
+			set: func() {
+				arguments.Region = "the_north_pacific"
+				arguments.Delegate = "mancheseva_city"
+				arguments.Excluded = []ns.NationName{"le_libertia"}
+				arguments.Base = &one
+				arguments.Limit = &eight
+				arguments.CitizensURL = "https://example.com/citizens.txt"
+				arguments.CacheDir = "elsewhere"
+			},
+			want: func(c *config.Config) {
+				c.Region = "the_north_pacific"
+				c.Delegate = "mancheseva_city"
+				c.Excluded = []ns.NationName{"le_libertia"}
+				c.Base = 1
+				c.Limit = 8
+				c.Citizens.URL = "https://example.com/citizens.txt"
+				c.CacheDir = "elsewhere"
+			},
+		},
+		{
+			name: "tiers without caps",
+			file: tiers,
+			set:  func() {},
+			want: func(*config.Config) {},
+		},
+		{
+			name:    "tiers with a cap",
+			file:    tiers,
+			set:     func() { arguments.Citizen = &eight },
+			wantErr: "-b, -e and -c",
+		},
+	}
+
+	saved := arguments
+	t.Cleanup(func() { arguments = saved })
+
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), config.FileName)
+		if err := os.WriteFile(path, []byte(test.file), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		arguments = saved
+		arguments.Config = path
+		test.set()
+
+		got, err := settings()
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: settings() error = %v, want one mentioning %q", test.name, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		want, err := config.Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.want(&want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: settings() = %+v, want %+v", test.name, got, want)
+		}
+	}
+}
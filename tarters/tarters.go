@@ -0,0 +1,189 @@
+package tarters
+
+import (
+	"fmt"
+	"os"
+
+	"rsc-tools/internal/citizens"
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/policy"
+	"rsc-tools/internal/report"
+	"rsc-tools/internal/roster"
+	"rsc-tools/internal/set"
+	"rsc-tools/internal/source"
+)
+
+type Args struct {
+	User     ns.NationName
+	Citizens citizens.Source
+	Delegate ns.NationName
+	Region   string
+	Excluded []ns.NationName
+	Policy   policy.Policy
+	Limit    int
+	Source   source.Options
+	Output   report.Options
+
+	// Nations, if not nil, limits the targets to these nations.
+	Nations []ns.NationName
+}
+
+type Targets struct {
+	Endorse   []ns.NationName
+	Unendorse []ns.NationName
+}
+
+func getEndorsementNumbers(g *graph.EndorsementGraph) (map[ns.NationName]int, error) {
+	endorsements := make(map[ns.NationName]int)
+
+	nations, err := g.Nations()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nation := range nations {
+		endorsements[nation], err = g.InDegree(nation)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return addAllWAs(g, endorsements)
+}
+
+func addAllWAs(g *graph.EndorsementGraph, nations map[ns.NationName]int) (map[ns.NationName]int, error) {
+	wa_nations, err := g.WANations()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nation := range wa_nations {
+		if _, ok := nations[nation]; !ok {
+			nations[nation] = 0
+		}
+	}
+
+	return nations, nil
+}
+
+// getNationsEndorsedBy returns the nations in the region that target is
+// endorsing. The API cannot report this, so unless g is already read from the
+// dump, the region is read from the nations dump named in opts or the day's
+// cached dump.
+func getNationsEndorsedBy(g *graph.EndorsementGraph, client *ns.Client, opts source.Options, target ns.NationName) ([]ns.NationName, error) {
+	if opts.Kind != source.KindDump {
+		opts.Kind = source.KindDump
+
+		src, err := source.Open(opts, client, g.Region)
+		if err != nil {
+			return nil, err
+		}
+
+		g = graph.New(src, g.Region)
+	}
+
+	return g.EndorsedBy(target)
+}
+
+func getTargets(args Args, was map[ns.NationName]int, members policy.Roster, self_endorsing []ns.NationName) Targets {
+	targets := Targets{}
+	excluded := set.New(args.Excluded...)
+	endorsing := set.New(self_endorsing...)
+	only := set.New(args.Nations...)
+
+	for nation, endorsements := range was {
+		if excluded.Has(nation) || nation == args.Delegate {
+			continue
+		}
+		if args.Nations != nil && !only.Has(nation) {
+			continue
+		}
+
+		endocap := args.Policy.CapFor(members.Nation(nation))
+
+		if endorsing.Has(nation) {
+			if endorsements > endocap {
+				targets.Unendorse = append(targets.Unendorse, nation)
+			}
+		} else if endorsements < endocap && endocap-endorsements > args.Limit {
+			targets.Endorse = append(targets.Endorse, nation)
+		}
+	}
+
+	ns.SortNames(targets.Endorse)
+	ns.SortNames(targets.Unendorse)
+
+	return targets
+}
+
+func buildReport(targets Targets) report.Report {
+	table := report.Table{Name: "Targets", Columns: []string{"nation", "action", "link"}}
+
+	for _, group := range []struct {
+		action  string
+		nations []ns.NationName
+	}{
+		{"endorse", targets.Endorse},
+		{"unendorse", targets.Unendorse},
+	} {
+		for _, nation := range group.nations {
+			link := report.Link{Text: string(nation), URL: fmt.Sprintf("https://www.nationstates.net/nation=%s#composebutton", nation)}
+			table.Rows = append(table.Rows, []interface{}{nation, group.action, link})
+		}
+	}
+
+	return report.Report{Name: "tarters", Title: "Targets", Tables: []report.Table{table}}
+}
+
+// Run reports the nations args.User should endorse or unendorse to stay within
+// the region's endocap.
+func Run(args Args) error {
+	client := ns.NewClient("Tarters", args.User.String())
+
+	src, err := source.Open(args.Source, client, args.Region)
+	if err != nil {
+		return err
+	}
+
+	g := graph.New(src, args.Region)
+
+	args.Delegate, err = source.CheckDelegate(src, args.Region, args.Delegate)
+	if err != nil {
+		return err
+	}
+
+	members, err := roster.Load(src, g, args.Citizens, args.Delegate, args.Policy)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "Getting nations and endorsements")
+	endorsements, err := getEndorsementNumbers(g)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "Getting nations that you are endorsing (this uses the daily dump and may take a minute to process)")
+	endorsing, err := getNationsEndorsedBy(g, client, args.Source, args.User)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "Getting targets")
+	targets := getTargets(
+		args,
+		endorsements,
+		members,
+		endorsing,
+	)
+
+	path, err := report.Save(buildReport(targets), args.Output, report.HTML)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Targets written to %s\n", path)
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package tarters
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/nstest"
+	"rsc-tools/internal/policy"
+	"rsc-tools/internal/source"
+)
+
+// TestAddAllWAs checks that WA members with no endorsements are added as
+// targets, since new joiners are the nations tarters should surface first.
+// tarters once parsed the wanations response into the wrong variable, so no
+// member was ever added. It reads the fixture the ns package tests WANations
+// with.
+func TestAddAllWAs(t *testing.T) {
+	body, err := os.ReadFile("../internal/ns/testdata/region_wanations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	src := source.API{
+		Client: &ns.Client{BaseURL: server.URL, HTTPClient: server.Client()},
+		Region: "europeia",
+	}
+
+	got, err := addAllWAs(graph.New(src, "europeia"), map[ns.NationName]int{"le_libertia": 40, "upc": 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[ns.NationName]int{
+		"le_libertia":     40,
+		"upc":             12,
+		"mancheseva_city": 0,
+		"pichtonia":       0,
+		"new_joiner":      0,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("addAllWAs() = %v, want %v", got, want)
+	}
+}
+
+func TestGetTargets(t *testing.T) {
+	// Caps are 10, 25 and 50; nations more than 5 below their cap are
+	// endorsed.
+	members := policy.NewRoster(
+		[]ns.NationName{"citizen", "citizen_near_cap"},
+		[]ns.NationName{"standard", "citizen", "citizen_near_cap", "over_standard"},
+		nil,
+	)
+
+	tests := []struct {
+		name          string
+		was           map[ns.NationName]int
+		selfEndorsing []ns.NationName
+		want          Targets
+	}{
+		{
+			name: "nations well under their cap are endorsed",
+			was:  map[ns.NationName]int{"base": 4, "standard": 19, "citizen": 44},
+			want: Targets{Endorse: []ns.NationName{"base", "citizen", "standard"}},
+		},
+		{
+			name: "nations within the limit of their cap are left alone",
+			was:  map[ns.NationName]int{"base": 5, "standard": 20, "citizen_near_cap": 45},
+		},
+		{
+			name: "new WA members with no endorsements are endorsed",
+			was:  map[ns.NationName]int{"new_joiner": 0},
+			want: Targets{Endorse: []ns.NationName{"new_joiner"}},
+		},
+		{
+			name:          "endorsed nations over their cap are unendorsed",
+			was:           map[ns.NationName]int{"base": 11, "over_standard": 26, "citizen": 50},
+			selfEndorsing: []ns.NationName{"base", "over_standard", "citizen"},
+			want:          Targets{Unendorse: []ns.NationName{"base", "over_standard"}},
+		},
+		{
+			name:          "endorsed nations under their cap are not endorsed again",
+			was:           map[ns.NationName]int{"base": 0},
+			selfEndorsing: []ns.NationName{"base"},
+		},
+		{
+			name: "the delegate and excluded nations are skipped",
+			was:  map[ns.NationName]int{"le_libertia": 0, "excluded": 0},
+		},
+	}
+
+	args := Args{
+		Delegate: "le_libertia",
+		Excluded: []ns.NationName{"excluded"},
+		Policy:   policy.Standard(10, 25, 50),
+		Limit:    5,
+	}
+
+	for _, test := range tests {
+		got := getTargets(args, test.was, members, test.selfEndorsing)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: getTargets() = %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func BenchmarkGetTargets(b *testing.B) {
+	big := nstest.SyntheticRegion("the_north_pacific", 10000)
+
+	was := make(map[ns.NationName]int)
+	var citizens, endorsers, excluded, endorsing []ns.NationName
+	for i, nation := range big.Nations {
+		name := ns.NationName(nation.Name)
+		was[name] = len(nation.Endorsements)
+		if i%3 == 0 {
+			citizens = append(citizens, name)
+		}
+		if i%2 == 0 {
+			endorsers = append(endorsers, name)
+		}
+		if i%100 == 0 {
+			excluded = append(excluded, name)
+		}
+		if i%5 == 0 {
+			endorsing = append(endorsing, name)
+		}
+	}
+
+	args := Args{Delegate: ns.NationName(big.Delegate), Excluded: excluded, Policy: policy.Standard(10, 25, 50), Limit: 5}
+	members := policy.NewRoster(citizens, endorsers, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		getTargets(args, was, members, endorsing)
+	}
+}
@@ -0,0 +1,206 @@
+package violators
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"rsc-tools/internal/citizens"
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/policy"
+	"rsc-tools/internal/report"
+	"rsc-tools/internal/roster"
+	"rsc-tools/internal/set"
+	"rsc-tools/internal/source"
+)
+
+// DefaultTop is how many violators are listed, and how many endorsers
+// checks, unless told otherwise, so that both act on the same list.
+const DefaultTop = 20
+
+type Args struct {
+	User     ns.NationName
+	Citizens citizens.Source
+	Delegate ns.NationName
+	Region   string
+	Excluded []ns.NationName
+	Policy   policy.Policy
+	Source   source.Options
+	Output   report.Options
+
+	// Nations, if not nil, limits the check to these nations.
+	Nations []ns.NationName
+
+	// Top, if positive, keeps only the Top nations furthest over their cap,
+	// breaking ties by name.
+	Top int
+}
+
+// Violator is a nation over its endocap and by how many endorsements, with
+// what decided its cap: the tier it fell into, that tier's cap, its
+// endorsements, and whether it endorses the delegate and is a citizen.
+type Violator struct {
+	Name             ns.NationName
+	Over             int
+	Tier             string
+	Cap              int
+	Endorsements     int
+	EndorsesDelegate bool
+	Citizen          bool
+}
+
+func getTopViolators(g *graph.EndorsementGraph, args Args, members policy.Roster) ([]Violator, error) {
+	nations, err := g.Nations()
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Nations != nil {
+		nations = set.New(args.Nations...).Filter(nations)
+	}
+
+	violators := make([]Violator, 0)
+
+	excluded := set.New(args.Excluded...)
+	for _, nation := range nations {
+		if excluded.Has(nation) || nation == args.Delegate {
+			continue
+		}
+
+		count, err := g.InDegree(nation)
+		if err != nil {
+			return nil, err
+		}
+
+		n := members.Nation(nation)
+		tier := args.Policy.TierFor(n)
+		if count > tier.Cap {
+			violators = append(violators, Violator{nation, count - tier.Cap, tier.Name, tier.Cap, count, n.EndorsesDelegate, n.Citizen})
+		}
+	}
+
+	sort.Slice(violators, func(i, j int) bool {
+		if violators[i].Over != violators[j].Over {
+			return violators[i].Over > violators[j].Over
+		}
+		return violators[i].Name < violators[j].Name
+	})
+
+	return top(violators, args.Top), nil
+}
+
+// top returns the first n violators, or all of them if n is not positive.
+func top(violators []Violator, n int) []Violator {
+	if n > 0 && len(violators) > n {
+		return violators[:n]
+	}
+
+	return violators
+}
+
+// summarize totals violators by tier, in the policy's order, followed by a
+// row for all tiers together. Each row gives the tier, how many nations are
+// over its cap, their overage summed and the furthest over of them.
+func summarize(p policy.Policy, violators []Violator) report.Table {
+	table := report.Table{Name: "Summary", Columns: []string{"tier", "violators", "overage", "largest offender"}}
+
+	type totals struct {
+		count   int
+		overage int
+		largest ns.NationName
+	}
+
+	byTier := make(map[string]*totals)
+	var all totals
+	for _, tier := range p.Tiers {
+		byTier[tier.Name] = &totals{}
+	}
+
+	// violators are sorted, so the first seen in each tier is the largest.
+	for _, v := range violators {
+		for _, t := range []*totals{byTier[v.Tier], &all} {
+			if t == nil {
+				continue
+			}
+			if t.count == 0 {
+				t.largest = v.Name
+			}
+			t.count++
+			t.overage += v.Over
+		}
+	}
+
+	for _, tier := range p.Tiers {
+		t := byTier[tier.Name]
+		table.Rows = append(table.Rows, []interface{}{tier.Name, t.count, t.overage, t.largest})
+	}
+	table.Rows = append(table.Rows, []interface{}{"total", all.count, all.overage, all.largest})
+
+	return table
+}
+
+func buildReport(args Args, violators []Violator) report.Report {
+	table := report.Table{
+		Name:    "Violators",
+		Columns: []string{"nation", "over", "tier", "cap", "endorsements", "endorses delegate", "citizen"},
+		Empty:   "No endocap violators.",
+	}
+	for _, v := range top(violators, args.Top) {
+		table.Rows = append(table.Rows, []interface{}{v.Name, v.Over, v.Tier, v.Cap, v.Endorsements, v.EndorsesDelegate, v.Citizen})
+	}
+
+	return report.Report{
+		Name:   "violators",
+		Title:  "Endocap Violators",
+		Tables: []report.Table{table, summarize(args.Policy, violators)},
+	}
+}
+
+// Find works out the region's endocap violators in g, most over their cap
+// first. Other tools use it so that they act on the same list violators
+// reports.
+func Find(src source.Source, g *graph.EndorsementGraph, args Args) ([]Violator, error) {
+	var err error
+	args.Delegate, err = source.CheckDelegate(src, args.Region, args.Delegate)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := roster.Load(src, g, args.Citizens, args.Delegate, args.Policy)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(os.Stderr, "Getting nations and endorsement numbers")
+	return getTopViolators(g, args, members)
+}
+
+// Run reports the nations exceeding their endocap and by how much.
+func Run(args Args) error {
+	client := ns.NewClient("Violators", args.User.String())
+
+	src, err := source.Open(args.Source, client, args.Region)
+	if err != nil {
+		return err
+	}
+
+	// Every violator is found so that the summary covers them all; only
+	// the top ones are listed.
+	all := args
+	all.Top = 0
+
+	violators, err := Find(src, graph.New(src, args.Region), all)
+	if err != nil {
+		return err
+	}
+
+	path, err := report.Save(buildReport(args, violators), args.Output, report.Text)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Results written to %s\n", path)
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package violators
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"rsc-tools/internal/graph"
+	"rsc-tools/internal/ns"
+	"rsc-tools/internal/nstest"
+	"rsc-tools/internal/policy"
+	"rsc-tools/internal/report"
+)
+
+// region has a delegate and nations on each tier. With caps of 2, 4 and 6:
+// a endorses the delegate and is 2 over the standard cap, c is a citizen 3
+// over the citizen cap, x does not endorse the delegate and is 1 over the base
+// cap, and y is 7 over but usually excluded.
+var region = nstest.Region{
+	Name:     "europeia",
+	Delegate: "le_libertia",
+	Nations: []nstest.Nation{
+		{Name: "le_libertia", WA: true, Endorsements: []string{"a", "b", "c", "x", "y", "z"}},
+		{Name: "a", WA: true, Endorsements: nstest.Names("a", 6)},
+		{Name: "b", WA: true, Endorsements: nstest.Names("b", 6)},
+		{Name: "c", WA: true, Endorsements: nstest.Names("c", 9)},
+		{Name: "x", WA: true, Endorsements: nstest.Names("x", 3)},
+		{Name: "y", WA: true, Endorsements: nstest.Names("y", 9)},
+		{Name: "z", WA: true, Endorsements: nstest.Names("z", 2)},
+		{Name: "n", WA: false},
+	},
+}
+
+var members = policy.NewRoster([]ns.NationName{"b", "c"}, []ns.NationName{"a", "b", "c"}, nil)
+
+func TestGetTopViolators(t *testing.T) {
+	tests := []struct {
+		name     string
+		excluded []ns.NationName
+		nations  []ns.NationName
+		policy   policy.Policy
+		want     []Violator
+	}{
+		{
+			name:     "standard tiers",
+			excluded: []ns.NationName{"y"},
+			policy:   policy.Standard(2, 4, 6),
+			want:     []Violator{{"c", 3, "citizen", 6, 9, true, true}, {"a", 2, "standard", 4, 6, true, false}, {"x", 1, "base", 2, 3, false, false}},
+		},
+		{
+			name:     "excluded nations are skipped",
+			excluded: []ns.NationName{"y", "c"},
+			policy:   policy.Standard(2, 4, 6),
+			want:     []Violator{{"a", 2, "standard", 4, 6, true, false}, {"x", 1, "base", 2, 3, false, false}},
+		},
+		{
+			name:   "nothing excluded",
+			policy: policy.Standard(2, 4, 6),
+			want:   []Violator{{"y", 7, "base", 2, 9, false, false}, {"c", 3, "citizen", 6, 9, true, true}, {"a", 2, "standard", 4, 6, true, false}, {"x", 1, "base", 2, 3, false, false}},
+		},
+		{
+			name:    "only the nations given",
+			nations: []ns.NationName{"a", "x", "z"},
+			policy:  policy.Standard(2, 4, 6),
+			want:    []Violator{{"a", 2, "standard", 4, 6, true, false}, {"x", 1, "base", 2, 3, false, false}},
+		},
+		{
+			name:    "an empty list of nations",
+			nations: []ns.NationName{},
+			policy:  policy.Standard(2, 4, 6),
+			want:    []Violator{},
+		},
+		{
+			name:     "flat cap ignores the delegate",
+			excluded: []ns.NationName{"y"},
+			policy:   policy.Standard(7, 7, 7),
+			want:     []Violator{{"c", 2, "citizen", 7, 9, true, true}},
+		},
+		{
+			name:     "no violators",
+			excluded: []ns.NationName{"y"},
+			policy:   policy.Standard(10, 10, 10),
+			want:     []Violator{},
+		},
+	}
+
+	server := nstest.NewServer(t, region)
+
+	for kind, src := range server.Sources() {
+		for _, test := range tests {
+			args := Args{Region: region.Name, Delegate: ns.NationName(region.Delegate), Excluded: test.excluded, Nations: test.nations, Policy: test.policy}
+
+			got, err := getTopViolators(graph.New(src, region.Name), args, members)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", kind, test.name, err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s/%s: getTopViolators() = %v, want %v", kind, test.name, got, test.want)
+			}
+		}
+	}
+}
+
+func TestGetTopViolatorsKeepsTop(t *testing.T) {
+	// nation_i is endorsed by i+2 nations, so it is i over a base cap of 2.
+	big := nstest.Region{Name: "europeia", Delegate: "le_libertia"}
+	for i := 1; i <= 25; i++ {
+		name := fmt.Sprintf("nation_%d", i)
+		big.Nations = append(big.Nations, nstest.Nation{Name: name, WA: true, Endorsements: nstest.Names(name, i+2)})
+	}
+
+	server := nstest.NewServer(t, big)
+	args := Args{Region: big.Name, Delegate: ns.NationName(big.Delegate), Policy: policy.Standard(2, 2, 2), Top: 20}
+
+	got, err := getTopViolators(graph.New(server.APISource(), big.Name), args, policy.Roster{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 20 {
+		t.Fatalf("got %d violators, want 20", len(got))
+	}
+
+	if got[0] != (Violator{"nation_25", 25, "base", 2, 27, false, false}) || got[19] != (Violator{"nation_6", 6, "base", 2, 8, false, false}) {
+		t.Errorf("got %v first and %v last, want nation_25 and nation_6", got[0], got[19])
+	}
+}
+
+func TestGetTopViolatorsBreaksTiesByName(t *testing.T) {
+	tied := nstest.Region{
+		Name:     "europeia",
+		Delegate: "le_libertia",
+		Nations: []nstest.Nation{
+			{Name: "le_libertia", WA: true},
+			{Name: "c", WA: true, Endorsements: nstest.Names("c", 3)},
+			{Name: "a", WA: true, Endorsements: nstest.Names("a", 3)},
+			{Name: "b", WA: true, Endorsements: nstest.Names("b", 4)},
+			{Name: "d", WA: true, Endorsements: nstest.Names("d", 3)},
+		},
+	}
+
+	server := nstest.NewServer(t, tied)
+
+	for kind, src := range server.Sources() {
+		args := Args{Region: tied.Name, Delegate: ns.NationName(tied.Delegate), Policy: policy.Standard(2, 2, 2), Top: 3}
+
+		got, err := getTopViolators(graph.New(src, tied.Name), args, policy.Roster{})
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+
+		want := []Violator{{"b", 2, "base", 2, 4, false, false}, {"a", 1, "base", 2, 3, false, false}, {"c", 1, "base", 2, 3, false, false}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: getTopViolators() = %v, want %v", kind, got, want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name      string
+		violators []Violator
+		want      [][]interface{}
+	}{
+		{
+			name:      "every tier",
+			violators: []Violator{{"y", 7, "base", 2, 9, false, false}, {"c", 3, "citizen", 6, 9, true, true}, {"a", 2, "standard", 4, 6, true, false}, {"x", 1, "base", 2, 3, false, false}},
+			want: [][]interface{}{
+				{"citizen", 1, 3, ns.NationName("c")},
+				{"standard", 1, 2, ns.NationName("a")},
+				{"base", 2, 8, ns.NationName("y")},
+				{"total", 4, 13, ns.NationName("y")},
+			},
+		},
+		{
+			name: "no violators",
+			want: [][]interface{}{
+				{"citizen", 0, 0, ns.NationName("")},
+				{"standard", 0, 0, ns.NationName("")},
+				{"base", 0, 0, ns.NationName("")},
+				{"total", 0, 0, ns.NationName("")},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := summarize(policy.Standard(2, 4, 6), test.violators)
+
+		if !reflect.DeepEqual(got.Rows, test.want) {
+			t.Errorf("%s: summarize() = %v, want %v", test.name, got.Rows, test.want)
+		}
+
+		for _, column := range got.Columns {
+			if column == "nation" {
+				t.Errorf("%s: summary has a nation column, which would be read as a list of nations", test.name)
+			}
+		}
+	}
+}
+
+func TestBuildReportExplainsCap(t *testing.T) {
+	violators := []Violator{
+		{"a", 2, "standard", 4, 6, true, false},
+		{"x", 1, "base", 2, 3, false, true},
+	}
+
+	got := buildReport(Args{Policy: policy.Standard(2, 4, 6)}, violators).Tables[0]
+
+	wantColumns := []string{"nation", "over", "tier", "cap", "endorsements", "endorses delegate", "citizen"}
+	if !reflect.DeepEqual(got.Columns, wantColumns) {
+		t.Errorf("columns = %v, want %v", got.Columns, wantColumns)
+	}
+
+	wantRows := [][]interface{}{
+		{ns.NationName("a"), 2, "standard", 4, 6, true, false},
+		{ns.NationName("x"), 1, "base", 2, 3, false, true},
+	}
+	if !reflect.DeepEqual(got.Rows, wantRows) {
+		t.Errorf("rows = %v, want %v", got.Rows, wantRows)
+	}
+}
+
+// TestEmptyReportRoundTrip checks that a report with no violators, which
+// still has a summary, is read back by other tools as no nations.
+func TestEmptyReportRoundTrip(t *testing.T) {
+	r := buildReport(Args{Policy: policy.Standard(2, 4, 6)}, []Violator{})
+
+	for _, format := range []string{report.Text, report.JSON, report.CSV} {
+		path := filepath.Join(t.TempDir(), "violators."+format)
+		if _, err := report.Save(r, report.Options{Format: format, Path: path}, report.Text); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		got, err := report.ReadNations(path)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		if len(got) != 0 {
+			t.Errorf("%s: read back %q, want no nations", format, got)
+		}
+	}
+}
+
+func BenchmarkGetTopViolators(b *testing.B) {
+	big := nstest.SyntheticRegion("the_north_pacific", 10000)
+	server := nstest.NewServer(b, big)
+	g := graph.New(server.DumpSource(), big.Name)
+
+	var citizens, endorsers, excluded []ns.NationName
+	for i, nation := range big.Nations {
+		name := ns.NationName(nation.Name)
+		if i%3 == 0 {
+			citizens = append(citizens, name)
+		}
+		if i%2 == 0 {
+			endorsers = append(endorsers, name)
+		}
+		if i%100 == 0 {
+			excluded = append(excluded, name)
+		}
+	}
+
+	args := Args{Region: big.Name, Delegate: ns.NationName(big.Delegate), Excluded: excluded, Policy: policy.Standard(10, 25, 50)}
+	members := policy.NewRoster(citizens, endorsers, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := getTopViolators(g, args, members)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}